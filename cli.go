@@ -0,0 +1,173 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"StevenD2002/GoCommit/config"
+	"StevenD2002/GoCommit/git"
+	"StevenD2002/GoCommit/gocommit"
+)
+
+// cliMessage holds the message flags used to commit without the TUI.
+type cliMessage struct {
+	commitType string
+	scope      string
+	subject    string
+	body       string
+	breaking   bool
+}
+
+// provided reports whether any message flag was set.
+func (c cliMessage) provided() bool {
+	return c.commitType != "" || c.scope != "" || c.subject != "" || c.body != "" || c.breaking
+}
+
+// complete reports whether enough flags were set to commit without the TUI.
+func (c cliMessage) complete() bool {
+	return c.commitType != "" && c.subject != ""
+}
+
+// build turns the flags into a commit message, looking up the emoji for the
+// configured type.
+func (c cliMessage) build(cfg config.Config) (git.Message, error) {
+	return gocommit.Message(gocommit.Options{
+		Config:   &cfg,
+		Type:     c.commitType,
+		Scope:    c.scope,
+		Subject:  c.subject,
+		Body:     c.body,
+		Breaking: c.breaking,
+	})
+}
+
+// commitResult is the --json description of a commit.
+type commitResult struct {
+	Type     string   `json:"type"`
+	Scope    string   `json:"scope,omitempty"`
+	Subject  string   `json:"subject"`
+	Body     string   `json:"body,omitempty"`
+	Breaking bool     `json:"breaking"`
+	Footers  []string `json:"footers,omitempty"`
+	Message  string   `json:"message"`
+	SHA      string   `json:"sha,omitempty"`
+	DryRun   bool     `json:"dryRun,omitempty"`
+}
+
+// reporter prints the outcome of a non-interactive commit, either as text
+// or, for --json, as a single JSON object on stdout.
+type reporter struct {
+	json bool
+}
+
+// fail reports err, along with any git output, and returns code.
+func (r reporter) fail(code int, output string, err error) int {
+	if r.json {
+		if r.print(struct {
+			Error  string `json:"error"`
+			Output string `json:"output,omitempty"`
+		}{err.Error(), output}) != 0 {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+		return code
+	}
+	if output != "" {
+		fmt.Fprintln(os.Stderr, output)
+	}
+	fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	return code
+}
+
+// print writes v as JSON to stdout and returns the exit code: 0, or 1 when
+// stdout can't be written, which is reported on stderr.
+func (r reporter) print(v any) int {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing JSON: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// result describes msg for --json.
+func result(msg git.Message) commitResult {
+	return commitResult{
+		Type:     msg.Type,
+		Scope:    msg.Scope,
+		Subject:  msg.Subject,
+		Body:     msg.Body,
+		Breaking: msg.Breaking,
+		Footers:  msg.Footers(),
+		Message:  msg.String(),
+	}
+}
+
+// runNonInteractive commits straight from the flags and returns the process
+// exit code.
+func runNonInteractive(repo *git.Repo, cfg config.Config, opts git.CommitOptions, c cliMessage, r reporter) int {
+	msg, err := c.build(cfg)
+	if err != nil {
+		return r.fail(2, "", err)
+	}
+
+	if opts.DryRun {
+		if r.json {
+			res := result(msg)
+			res.DryRun = true
+			return r.print(res)
+		}
+		fmt.Println(msg)
+		return 0
+	}
+
+	if err := repo.Check(); err != nil {
+		return r.fail(1, "", err)
+	}
+
+	stagedFiles, err := repo.StagedFiles()
+	if err != nil {
+		return r.fail(1, "", err)
+	}
+	if op := repo.InProgress(); op != "" {
+		fmt.Fprintf(os.Stderr, "Warning: a %s is in progress; this commit will be part of it\n", op)
+	}
+	if len(stagedFiles) == 0 && !opts.Amend && !opts.AllowEmpty {
+		if r.json {
+			return r.fail(1, "", errors.New("no files staged for commit"))
+		}
+		fmt.Fprintln(os.Stderr, "No files staged for commit. Use 'git add' to stage files.")
+		return 1
+	}
+
+	output, err := repo.Commit(msg, opts)
+	if err != nil {
+		var exitErr *exec.ExitError
+		code := 1
+		if errors.As(err, &exitErr) {
+			code = exitErr.ExitCode()
+		}
+		return r.fail(code, output, err)
+	}
+
+	if !r.json {
+		fmt.Println(output)
+		if cfg.EditAfter {
+			amend := exec.Command("git", git.AmendArgs(opts)...)
+			amend.Dir, _ = repo.Root()
+			amend.Stdin, amend.Stdout, amend.Stderr = os.Stdin, os.Stdout, os.Stderr
+			if err := amend.Run(); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: git commit --amend failed (%v); the commit was kept as it was\n", err)
+			}
+		}
+		return 0
+	}
+	res := result(msg)
+	if res.SHA, err = repo.HeadSHA(); err != nil {
+		return r.fail(1, "", fmt.Errorf("reading HEAD: %w", err))
+	}
+	return r.print(res)
+}
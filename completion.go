@@ -0,0 +1,189 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"StevenD2002/GoCommit/config"
+	"StevenD2002/GoCommit/git"
+)
+
+// typesCommand is the hidden subcommand the completion scripts run to list
+// the commit types configured for the current repository.
+const typesCommand = "__types"
+
+// completionFlag describes a command line flag for the completion scripts.
+type completionFlag struct {
+	name  string
+	usage string
+	// takesValue is false for boolean flags.
+	takesValue bool
+}
+
+// completionFlags returns the long flags defined on the default flag set.
+// Single-letter shorthands are left out.
+func completionFlags() []completionFlag {
+	var flags []completionFlag
+	flag.VisitAll(func(f *flag.Flag) {
+		if len(f.Name) == 1 {
+			return
+		}
+		b, ok := f.Value.(interface{ IsBoolFlag() bool })
+		flags = append(flags, completionFlag{
+			name:       f.Name,
+			usage:      f.Usage,
+			takesValue: !ok || !b.IsBoolFlag(),
+		})
+	})
+	return flags
+}
+
+// runCompletion prints the completion script for the shell named in args
+// and returns the exit code.
+func runCompletion(args []string) int {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, "Usage: gocommit completion bash|zsh|fish")
+		return 2
+	}
+	flags := completionFlags()
+	switch args[0] {
+	case "bash":
+		writeBashCompletion(os.Stdout, flags)
+	case "zsh":
+		writeZshCompletion(os.Stdout, flags)
+	case "fish":
+		writeFishCompletion(os.Stdout, flags)
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unsupported shell %q (expected bash, zsh or fish)\n", args[0])
+		return 2
+	}
+	return 0
+}
+
+// printTypes lists the configured commit types, one per line, for the
+// completion scripts, which pass on the --cwd being completed. Errors print
+// nothing so completion degrades quietly.
+func printTypes(args []string) {
+	fs := flag.NewFlagSet(typesCommand, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	cwd := fs.String("cwd", "", "")
+	if fs.Parse(args) != nil {
+		return
+	}
+	root, _ := git.NewRepo(git.ExecRunner{Dir: *cwd}).Root()
+	cfg, _, err := config.Load(root)
+	if err != nil {
+		return
+	}
+	for _, t := range cfg.Types {
+		fmt.Println(t.Title)
+	}
+}
+
+func writeBashCompletion(w io.Writer, flags []completionFlag) {
+	var names, valueFlags []string
+	for _, f := range flags {
+		names = append(names, "--"+f.name)
+		if f.takesValue && f.name != "type" && f.name != "message-file" {
+			valueFlags = append(valueFlags, "--"+f.name)
+		}
+	}
+
+	fmt.Fprintf(w, `# bash completion for gocommit
+_gocommit() {
+	local cur="${COMP_WORDS[COMP_CWORD]}" prev="${COMP_WORDS[COMP_CWORD-1]}" cwd=() i
+	for ((i = 1; i < COMP_CWORD - 1; i++)); do
+		[[ ${COMP_WORDS[i]} == --cwd ]] && cwd=(--cwd "${COMP_WORDS[i+1]}")
+	done
+	case "$prev" in
+	--type)
+		COMPREPLY=($(compgen -W "$(gocommit %s "${cwd[@]}" 2>/dev/null)" -- "$cur"))
+		return
+		;;
+	--message-file)
+		COMPREPLY=($(compgen -f -- "$cur"))
+		return
+		;;
+	%s)
+		return
+		;;
+	completion)
+		COMPREPLY=($(compgen -W "bash zsh fish" -- "$cur"))
+		return
+		;;
+	esac
+	if [[ $COMP_CWORD -eq 1 && $cur != -* ]]; then
+		COMPREPLY=($(compgen -W "version completion revert types schema" -- "$cur"))
+		return
+	fi
+	COMPREPLY=($(compgen -W "%s" -- "$cur"))
+}
+complete -F _gocommit gocommit
+`, typesCommand, strings.Join(valueFlags, "|"), strings.Join(names, " "))
+}
+
+func writeZshCompletion(w io.Writer, flags []completionFlag) {
+	fmt.Fprintf(w, `#compdef gocommit
+
+_gocommit_types() {
+	local -a types cwd
+	local i=${words[(I)--cwd]}
+	(( i )) && cwd=(--cwd ${words[i+1]})
+	types=(${(f)"$(gocommit %s $cwd 2>/dev/null)"})
+	compadd -a types
+}
+
+_gocommit() {
+	if (( CURRENT == 2 )) && [[ $words[2] != -* ]]; then
+		compadd version completion revert types schema
+		return
+	fi
+	if [[ $words[2] == completion ]]; then
+		compadd bash zsh fish
+		return
+	fi
+	_arguments \
+`, typesCommand)
+	for _, f := range flags {
+		usage := strings.NewReplacer("'", `'\''`, "[", "(", "]", ")", ":", `\:`).Replace(f.usage)
+		spec := fmt.Sprintf("--%s[%s]", f.name, usage)
+		switch {
+		case f.name == "type":
+			spec += ":type:_gocommit_types"
+		case f.name == "message-file":
+			spec += ":file:_files"
+		case f.takesValue:
+			spec += ":value: "
+		}
+		fmt.Fprintf(w, "\t\t'%s' \\\n", spec)
+	}
+	fmt.Fprint(w, "\t\t'*: :'\n}\n\n_gocommit \"$@\"\n")
+}
+
+func writeFishCompletion(w io.Writer, flags []completionFlag) {
+	fmt.Fprint(w, `# fish completion for gocommit
+function __gocommit_cwd
+	set -l words (commandline -opc)
+	if set -l i (contains -i -- --cwd $words); and test $i -lt (count $words)
+		string join -- \n --cwd $words[(math $i + 1)]
+	end
+end
+`)
+	fmt.Fprint(w, "complete -c gocommit -n __fish_use_subcommand -xa 'version completion revert types schema'\n")
+	fmt.Fprint(w, "complete -c gocommit -n '__fish_seen_subcommand_from completion' -xa 'bash zsh fish'\n")
+	for _, f := range flags {
+		line := fmt.Sprintf("complete -c gocommit -l %s -d '%s'", f.name, strings.ReplaceAll(f.usage, "'", `\'`))
+		switch {
+		case f.name == "type":
+			line += fmt.Sprintf(" -xa '(gocommit %s (__gocommit_cwd) 2>/dev/null)'", typesCommand)
+		case f.name == "message-file":
+			line += " -rF"
+		case f.takesValue:
+			line += " -x"
+		}
+		fmt.Fprintln(w, line)
+	}
+}
@@ -0,0 +1,393 @@
+// Package config loads the .gocommit.json settings files.
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// FileName is the name of the config file looked up by Load.
+const FileName = ".gocommit.json"
+
+// Config holds the settings read from a .gocommit.json file.
+type Config struct {
+	Types   []Type `json:"types"`
+	NoEmoji bool   `json:"noEmoji"`
+	// Emojis overrides the emoji of types by title, so the default types
+	// can get other icons or none without being listed in full.
+	Emojis map[string]string `json:"emojis"`
+	// TypeOrder lists types, by title, to show first on the selection
+	// screen, and HiddenTypes leaves types off it; see ListedTypes.
+	TypeOrder   []string `json:"typeOrder"`
+	HiddenTypes []string `json:"hiddenTypes"`
+	// Sign passes -S to git commit, using SigningKey when set.
+	Sign       bool   `json:"sign"`
+	SigningKey string `json:"signingKey"`
+	// MaxHeaderLength is the longest header, including the type and scope
+	// prefix, that can be committed.
+	MaxHeaderLength int `json:"maxHeaderLength"`
+	// HeaderPattern, when set, is a regular expression every header must
+	// match before it can be committed.
+	HeaderPattern string `json:"headerPattern"`
+	// HeaderFormat lays out the header from {emoji}, {type}, {scope},
+	// {breaking} and {subject}, such as "[{type}] {subject}". Empty keeps
+	// the conventional "type(scope)!: subject".
+	HeaderFormat string `json:"headerFormat"`
+	// WrapBody hard-wraps the body at this column before committing. Zero
+	// commits it as typed.
+	WrapBody int `json:"wrapBody"`
+	// TicketPattern extracts a ticket key from the branch name, and
+	// PrependTicket adds that key to the subject by default.
+	TicketPattern string `json:"ticketPattern"`
+	PrependTicket bool   `json:"prependTicket"`
+	// BranchScope uses a scope derived from the branch name when none is
+	// entered. BranchScopePattern picks the part of the branch to use: its
+	// first group, or the whole match.
+	BranchScope        bool   `json:"branchScope"`
+	BranchScopePattern string `json:"branchScopePattern"`
+	// CheckMood shows an advisory when the subject isn't in the
+	// imperative mood.
+	CheckMood bool `json:"checkMood"`
+	// SpellCheck lists suspected typos in the subject, and Words adds
+	// project jargon to the dictionary.
+	SpellCheck bool     `json:"spellCheck"`
+	Words      []string `json:"words"`
+	// MaxChangedLines warns on the confirmation screen when the commit
+	// adds and removes more lines than this. Zero turns the check off.
+	MaxChangedLines int `json:"maxChangedLines"`
+	// Commitlint runs the repository's commitlint on the message before
+	// committing, when it is configured and installed there.
+	Commitlint bool `json:"commitlint"`
+	// StripPeriod and LowercaseSubject normalize the subject before it is
+	// committed, matching common commitlint rules.
+	StripPeriod      bool `json:"stripPeriod"`
+	LowercaseSubject bool `json:"lowercaseSubject"`
+	// CollapseWhitespace replaces runs of whitespace inside the subject
+	// with a single space.
+	CollapseWhitespace bool `json:"collapseWhitespace"`
+	// ProtectedBranches require typing "yes" before committing to them.
+	// Defaults to main and master; an empty list turns the prompt off.
+	ProtectedBranches []string `json:"protectedBranches"`
+	// ConfirmDetached requires the same "yes" before committing on a
+	// detached HEAD.
+	ConfirmDetached bool `json:"confirmDetached"`
+	// Gitmoji switches to gitmoji style headers such as ":sparkles: add
+	// feature", committing either the "shortcode" or the "emoji".
+	Gitmoji string `json:"gitmoji"`
+	// ViaFile commits with git commit -F and a temporary file instead
+	// of -m, for hook-based workflows.
+	ViaFile bool `json:"viaFile"`
+	// NoStickyType starts the type list at the first type instead of the
+	// one used for the last commit in the repository.
+	NoStickyType bool `json:"noStickyType"`
+	// StageAll stages the changes to tracked files before committing, like
+	// git commit -a. Untracked files are left alone.
+	StageAll bool `json:"stageAll"`
+	// IdleTimeout quits the TUI without committing after this many seconds
+	// without input. Zero, the default, waits forever.
+	IdleTimeout int `json:"idleTimeout"`
+	// Push runs git push after each successful commit.
+	Push bool `json:"push"`
+	// OpenPR opens a GitHub pull request with gh after the push, titled
+	// and described like the commit. It turns on Push.
+	OpenPR bool `json:"openPR"`
+	// EditAfter reopens each new commit with git commit --amend, so its
+	// message can be refined in the editor.
+	EditAfter bool `json:"editAfter"`
+	// Minimal hides the staged files panel and the step hints, leaving room
+	// for the current step on small terminals.
+	Minimal bool `json:"minimal"`
+	// AllowDuplicate commits a subject that repeats the last commit's
+	// without asking first.
+	AllowDuplicate bool `json:"allowDuplicate"`
+	// Wip is the commit created by the quick WIP shortcut.
+	Wip Wip `json:"wip"`
+	// Templates are offered in a picker before choosing the commit type.
+	Templates []Template `json:"templates"`
+	// Theme customizes the TUI colors.
+	Theme Theme `json:"theme"`
+	// Keys remaps TUI actions, such as "quit" or "next", to lists of keys.
+	Keys map[string][]string `json:"keys"`
+}
+
+const (
+	// defaultMaxHeaderLength is used when the config doesn't set a limit.
+	defaultMaxHeaderLength = 72
+	// defaultTicketPattern matches keys such as PROJ-123.
+	defaultTicketPattern = `[A-Z][A-Z0-9]+-\d+`
+)
+
+// applyDefaults fills in settings the config file left unset.
+func (c *Config) applyDefaults() {
+	if len(c.Types) == 0 {
+		c.Types = defaultTypes
+	}
+	if c.MaxHeaderLength <= 0 {
+		c.MaxHeaderLength = defaultMaxHeaderLength
+	}
+	if c.TicketPattern == "" {
+		c.TicketPattern = defaultTicketPattern
+	}
+	if c.BranchScopePattern == "" {
+		c.BranchScopePattern = defaultBranchScopePattern
+	}
+	if c.ProtectedBranches == nil {
+		c.ProtectedBranches = []string{"main", "master"}
+	}
+	if c.Wip.Type == "" {
+		c.Wip.Type = "chore"
+	}
+	if c.Wip.Message == "" {
+		c.Wip.Message = "wip"
+	}
+}
+
+// Wip sets the type and subject of quick WIP commits.
+type Wip struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+// Type describes a single commit type entry in the config file.
+type Type struct {
+	Title string `json:"title"`
+	Desc  string `json:"desc"`
+	Emoji string `json:"emoji"`
+	// Gitmoji is the shortcode used in gitmoji mode, such as ":sparkles:".
+	// The default types have one built in.
+	Gitmoji string `json:"gitmoji,omitempty"`
+	// Subject is a template the typed subject is put in, such as
+	// "[core] {msg}", for teams that format some types' subjects.
+	Subject string `json:"subject,omitempty"`
+	// Keywords are other words the type list filter finds the type by,
+	// such as "bug" for fix.
+	Keywords []string `json:"keywords,omitempty"`
+}
+
+// subjectPlaceholder marks where the typed subject goes in Type.Subject.
+const subjectPlaceholder = "{msg}"
+
+// ApplySubject puts subject into template. An empty template or subject
+// leaves subject as is, and so does a subject that already has the
+// template's prefix and suffix, as one read back from an existing commit.
+func ApplySubject(template, subject string) string {
+	if template == "" || subject == "" {
+		return subject
+	}
+	prefix, suffix, _ := strings.Cut(template, subjectPlaceholder)
+	if strings.HasPrefix(subject, prefix) && strings.HasSuffix(subject, suffix) && len(subject) > len(prefix)+len(suffix) {
+		return subject
+	}
+	return prefix + subject + suffix
+}
+
+// EmojiFor returns the emoji shown and committed for t, applying the Emojis
+// overrides. An override may be empty to drop the emoji.
+func (c Config) EmojiFor(t Type) string {
+	if emoji, ok := c.Emojis[t.Title]; ok {
+		return emoji
+	}
+	return t.Emoji
+}
+
+// validateEmojis warns about overrides for types that don't exist.
+func validateEmojis(c Config) []string {
+	var warnings []string
+	for title := range c.Emojis {
+		if !slices.ContainsFunc(c.Types, func(t Type) bool { return t.Title == title }) {
+			warnings = append(warnings, fmt.Sprintf("emojis: %q is not a commit type", title))
+		}
+	}
+	slices.Sort(warnings)
+	return warnings
+}
+
+var defaultTypes = []Type{
+	{Title: "feat", Desc: "A new feature", Emoji: "📦", Keywords: []string{"add", "new", "introduce"}},
+	{Title: "fix", Desc: "A bug fix", Emoji: "🔨", Keywords: []string{"bug", "hotfix", "patch", "crash"}},
+	{Title: "docs", Desc: "Documentation only changes", Emoji: "📝", Keywords: []string{"readme", "comment", "guide"}},
+	{Title: "style", Desc: "Changes that do not affect the meaning of the code", Emoji: "🎨", Keywords: []string{"format", "lint", "whitespace"}},
+	{Title: "refactor", Desc: "A code change that neither fixes a bug nor adds a feature", Emoji: "🧹", Keywords: []string{"cleanup", "restructure", "rename"}},
+	{Title: "perf", Desc: "A code change that improves performance", Emoji: "🚀", Keywords: []string{"speed", "fast", "optimize", "memory"}},
+	{Title: "test", Desc: "Adding missing tests or correcting existing tests", Emoji: "🧪", Keywords: []string{"spec", "coverage"}},
+	{Title: "chore", Desc: "Changes to the build process or auxiliary tools", Emoji: "👷", Keywords: []string{"build", "deps", "ci", "release", "tooling"}},
+}
+
+// searchPaths returns the locations of config files, in order of
+// preference: the repository root first, then the global files in the
+// user's home and config directories.
+func searchPaths(repoRoot string) []string {
+	var paths []string
+	if repoRoot != "" {
+		paths = append(paths, filepath.Join(repoRoot, FileName))
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		paths = append(paths, filepath.Join(home, FileName))
+	}
+	if dir, err := os.UserConfigDir(); err == nil {
+		paths = append(paths, filepath.Join(dir, "gocommit", "config.json"))
+	}
+	return paths
+}
+
+// Load merges the config layers and returns the result along with any
+// non-fatal warnings. Each layer overrides the ones before it field by
+// field, so a repository only needs to list what it changes:
+//
+//  1. the built-in defaults
+//  2. the global <user config dir>/gocommit/config.json
+//  3. the global ~/.gocommit.json
+//  4. .gocommit.json in the repository root
+//  5. GOCOMMIT_* environment variables, such as GOCOMMIT_NO_EMOJI=true
+//
+// Lists such as "types" are replaced as a whole. repoRoot may be empty
+// outside a repository.
+func Load(repoRoot string) (Config, []string, error) {
+	var cfg Config
+	var warnings []string
+	source := "config"
+
+	paths := searchPaths(repoRoot)
+	slices.Reverse(paths)
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return cfg, nil, err
+		}
+		layerWarnings, err := cfg.merge(data)
+		if err != nil {
+			return cfg, nil, fmt.Errorf("%s: %w", path, err)
+		}
+		warnings = append(warnings, layerWarnings...)
+		source = path
+	}
+
+	if data, ok, err := envLayer(); err != nil {
+		return cfg, nil, err
+	} else if ok {
+		layerWarnings, err := cfg.merge(data)
+		if err != nil {
+			return cfg, nil, fmt.Errorf("environment: %w", err)
+		}
+		warnings = append(warnings, layerWarnings...)
+		source = "environment"
+	}
+
+	cfg.applyDefaults()
+	templateWarnings, err := validateTemplates(cfg.Templates, cfg.Types)
+	if err != nil {
+		return cfg, nil, fmt.Errorf("%s: %w", source, err)
+	}
+	warnings = append(warnings, templateWarnings...)
+	gitmojiWarnings, err := validateGitmoji(cfg)
+	if err != nil {
+		return cfg, nil, fmt.Errorf("%s: %w", source, err)
+	}
+	warnings = append(warnings, gitmojiWarnings...)
+	headerWarnings, err := validateHeaderFormat(cfg)
+	if err != nil {
+		return cfg, nil, fmt.Errorf("%s: %w", source, err)
+	}
+	warnings = append(warnings, headerWarnings...)
+	orderWarnings, err := validateTypeOrder(cfg)
+	if err != nil {
+		return cfg, nil, fmt.Errorf("%s: %w", source, err)
+	}
+	warnings = append(warnings, orderWarnings...)
+	warnings = append(warnings, validateEmojis(cfg)...)
+	return cfg, warnings, nil
+}
+
+// merge applies a JSON layer on top of c. The layer is decoded strictly,
+// and its own values and types are validated before they replace the
+// current ones.
+func (c *Config) merge(data []byte) ([]string, error) {
+	var layer Config
+	if err := decodeLayer(data, &layer); err != nil {
+		return nil, err
+	}
+	if err := validateLayer(layer); err != nil {
+		return nil, err
+	}
+	warnings, err := validateTypes(layer.Types)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("parsing: %w", err)
+	}
+	return warnings, nil
+}
+
+// FilePath returns the most specific config file that exists, or where a new
+// one should be created: the repository root, or the home directory outside a
+// repository.
+func FilePath(repoRoot string) string {
+	paths := searchPaths(repoRoot)
+	for _, path := range paths {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	if len(paths) == 0 {
+		return FileName
+	}
+	return paths[0]
+}
+
+// SaveTypes writes the commit types to the config file at path, keeping its
+// other settings as they are.
+func SaveTypes(path string, types []Type) error {
+	if _, err := validateTypes(types); err != nil {
+		return err
+	}
+
+	settings := make(map[string]json.RawMessage)
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(data, &settings); err != nil {
+			return fmt.Errorf("parsing %s: %w", path, err)
+		}
+	case !errors.Is(err, os.ErrNotExist):
+		return err
+	}
+
+	raw, err := json.Marshal(types)
+	if err != nil {
+		return err
+	}
+	settings["types"] = raw
+	out, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(out, '\n'), 0o644)
+}
+
+// validateTypes rejects entries without a title and warns about duplicates.
+func validateTypes(types []Type) ([]string, error) {
+	var warnings []string
+	seen := make(map[string]bool)
+	for i, t := range types {
+		if strings.TrimSpace(t.Title) == "" {
+			return nil, fmt.Errorf("commit type %d has an empty title", i+1)
+		}
+		if seen[t.Title] {
+			warnings = append(warnings, fmt.Sprintf("duplicate commit type %q", t.Title))
+		}
+		seen[t.Title] = true
+		if t.Subject != "" && strings.Count(t.Subject, subjectPlaceholder) != 1 {
+			return nil, fmt.Errorf("commit type %q: the subject template must contain %s exactly once", t.Title, subjectPlaceholder)
+		}
+	}
+	return warnings, nil
+}
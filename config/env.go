@@ -0,0 +1,87 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// envPrefix starts the environment variables that override the config.
+const envPrefix = "GOCOMMIT_"
+
+// envName returns the environment variable for a JSON field name, such as
+// GOCOMMIT_MAX_HEADER_LENGTH for maxHeaderLength. A run of capitals is one
+// word, so openPR gives GOCOMMIT_OPEN_PR.
+func envName(field string) string {
+	var b strings.Builder
+	b.WriteString(envPrefix)
+	runes := []rune(field)
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			// A capital starts a word after a lower case letter, or ends a
+			// run of capitals when a lower case letter follows.
+			prev := runes[i-1]
+			if !unicode.IsUpper(prev) || i+1 < len(runes) && unicode.IsLower(runes[i+1]) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
+
+// envLayer collects the GOCOMMIT_* variables into a JSON config layer. ok is
+// false when none are set; empty ones are ignored. Values of string settings
+// are taken literally, booleans accept 1/0, true/false, yes/no and on/off,
+// and the others are parsed as JSON, as in GOCOMMIT_TYPES='[{"title":"feat"}]'.
+func envLayer() (data []byte, ok bool, err error) {
+	layer := make(map[string]json.RawMessage)
+	t := reflect.TypeOf(Config{})
+	for i := range t.NumField() {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		value := os.Getenv(envName(name))
+		if value == "" {
+			continue
+		}
+		raw, err := envValue(field.Type, value)
+		if err != nil {
+			return nil, false, fmt.Errorf("%s: %w", envName(name), err)
+		}
+		layer[name] = raw
+	}
+	if len(layer) == 0 {
+		return nil, false, nil
+	}
+	data, err = json.Marshal(layer)
+	return data, true, err
+}
+
+// envValue converts the value of a variable to JSON for a field of type t,
+// checking that it fits.
+func envValue(t reflect.Type, value string) (json.RawMessage, error) {
+	switch t.Kind() {
+	case reflect.String:
+		return json.Marshal(value)
+	case reflect.Bool:
+		switch strings.ToLower(strings.TrimSpace(value)) {
+		case "1", "true", "yes", "on":
+			return json.RawMessage("true"), nil
+		case "0", "false", "no", "off":
+			return json.RawMessage("false"), nil
+		}
+		return nil, fmt.Errorf("%q is not a boolean (use 1/0, true/false, yes/no or on/off)", value)
+	}
+
+	raw := json.RawMessage(value)
+	if err := json.Unmarshal(raw, reflect.New(t).Interface()); err != nil {
+		return nil, fmt.Errorf("invalid value %q", value)
+	}
+	return raw, nil
+}
@@ -0,0 +1,119 @@
+package config
+
+import "fmt"
+
+// Gitmoji styles for Config.Gitmoji.
+const (
+	GitmojiShortcode = "shortcode"
+	GitmojiEmoji     = "emoji"
+)
+
+// defaultGitmojis are the gitmoji shortcodes for the default commit types.
+var defaultGitmojis = map[string]string{
+	"feat":     ":sparkles:",
+	"fix":      ":bug:",
+	"docs":     ":memo:",
+	"style":    ":art:",
+	"refactor": ":recycle:",
+	"perf":     ":zap:",
+	"test":     ":white_check_mark:",
+	"chore":    ":wrench:",
+}
+
+// gitmojiEmoji maps the gitmoji shortcodes to the emoji they stand for.
+var gitmojiEmoji = map[string]string{
+	":art:":                       "🎨",
+	":zap:":                       "⚡️",
+	":fire:":                      "🔥",
+	":bug:":                       "🐛",
+	":ambulance:":                 "🚑️",
+	":sparkles:":                  "✨",
+	":memo:":                      "📝",
+	":rocket:":                    "🚀",
+	":lipstick:":                  "💄",
+	":tada:":                      "🎉",
+	":white_check_mark:":          "✅",
+	":lock:":                      "🔒️",
+	":bookmark:":                  "🔖",
+	":rotating_light:":            "🚨",
+	":construction:":              "🚧",
+	":green_heart:":               "💚",
+	":arrow_down:":                "⬇️",
+	":arrow_up:":                  "⬆️",
+	":pushpin:":                   "📌",
+	":construction_worker:":       "👷",
+	":recycle:":                   "♻️",
+	":heavy_plus_sign:":           "➕",
+	":heavy_minus_sign:":          "➖",
+	":wrench:":                    "🔧",
+	":hammer:":                    "🔨",
+	":globe_with_meridians:":      "🌐",
+	":pencil2:":                   "✏️",
+	":rewind:":                    "⏪️",
+	":twisted_rightwards_arrows:": "🔀",
+	":package:":                   "📦️",
+	":truck:":                     "🚚",
+	":boom:":                      "💥",
+	":bento:":                     "🍱",
+	":wheelchair:":                "♿️",
+	":bulb:":                      "💡",
+	":speech_balloon:":            "💬",
+	":card_file_box:":             "🗃️",
+	":loud_sound:":                "🔊",
+	":mute:":                      "🔇",
+	":see_no_evil:":               "🙈",
+	":alembic:":                   "⚗️",
+	":label:":                     "🏷️",
+	":coffin:":                    "⚰️",
+	":test_tube:":                 "🧪",
+	":adhesive_bandage:":          "🩹",
+}
+
+// GitmojiToEmoji returns the emoji for a gitmoji shortcode, or the shortcode
+// itself when it isn't known.
+func GitmojiToEmoji(code string) string {
+	if emoji, ok := gitmojiEmoji[code]; ok {
+		return emoji
+	}
+	return code
+}
+
+// shortcode returns the gitmoji shortcode for t, falling back to the
+// built-in one for the default types.
+func (t Type) shortcode() string {
+	if t.Gitmoji != "" {
+		return t.Gitmoji
+	}
+	return defaultGitmojis[t.Title]
+}
+
+// GitmojiFor returns the gitmoji committed for t in the configured style, or
+// "" when gitmoji mode is off.
+func (c Config) GitmojiFor(t Type) string {
+	switch c.Gitmoji {
+	case GitmojiShortcode:
+		return t.shortcode()
+	case GitmojiEmoji:
+		return GitmojiToEmoji(t.shortcode())
+	}
+	return ""
+}
+
+// validateGitmoji checks the gitmoji style and warns about types without a
+// shortcode, which fall back to conventional headers.
+func validateGitmoji(c Config) ([]string, error) {
+	switch c.Gitmoji {
+	case "":
+		return nil, nil
+	case GitmojiShortcode, GitmojiEmoji:
+	default:
+		return nil, fmt.Errorf("unknown gitmoji style %q (expected %q or %q)", c.Gitmoji, GitmojiShortcode, GitmojiEmoji)
+	}
+	var warnings []string
+	for _, t := range c.Types {
+		if t.shortcode() == "" {
+			warnings = append(warnings, fmt.Sprintf("commit type %q has no gitmoji", t.Title))
+		}
+	}
+	return warnings, nil
+}
@@ -0,0 +1,38 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// headerPlaceholders are the fields a header format can reference.
+var headerPlaceholders = []string{"emoji", "type", "scope", "breaking", "subject"}
+
+// placeholderPattern matches a {name} placeholder.
+var placeholderPattern = regexp.MustCompile(`\{(\w+)\}`)
+
+// validateHeaderFormat rejects header formats with unknown placeholders or
+// without exactly one {subject}, and warns when gitmoji headers override
+// it.
+func validateHeaderFormat(c Config) ([]string, error) {
+	if c.HeaderFormat == "" {
+		return nil, nil
+	}
+	seen := make(map[string]int)
+	for _, match := range placeholderPattern.FindAllStringSubmatch(c.HeaderFormat, -1) {
+		if !slices.Contains(headerPlaceholders, match[1]) {
+			return nil, fmt.Errorf("headerFormat: unknown placeholder %s (expected {%s})", match[0], strings.Join(headerPlaceholders, "}, {"))
+		}
+		seen[match[1]]++
+	}
+	if seen["subject"] != 1 {
+		return nil, fmt.Errorf("headerFormat must contain {subject} exactly once")
+	}
+
+	if c.Gitmoji != "" {
+		return []string{"headerFormat is ignored for gitmoji headers"}, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,57 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ListedTypes returns the types offered on the selection screen: those in
+// TypeOrder first, in that order, then the rest in their configured order,
+// leaving out HiddenTypes. Types stays untouched, so hidden types can still
+// be committed with --type and kept by the type editor.
+func (c Config) ListedTypes() []Type {
+	var listed []Type
+	for _, title := range c.TypeOrder {
+		i := slices.IndexFunc(c.Types, func(t Type) bool { return t.Title == title })
+		if i < 0 || slices.ContainsFunc(listed, func(t Type) bool { return t.Title == title }) {
+			continue
+		}
+		listed = append(listed, c.Types[i])
+	}
+	for _, t := range c.Types {
+		if !slices.Contains(c.TypeOrder, t.Title) {
+			listed = append(listed, t)
+		}
+	}
+	return slices.DeleteFunc(listed, func(t Type) bool { return slices.Contains(c.HiddenTypes, t.Title) })
+}
+
+// validateTypeOrder warns about typeOrder and hiddenTypes entries that
+// aren't commit types, and about templates using a hidden type, which the
+// selection screen can't pick. Hiding every type is an error.
+func validateTypeOrder(c Config) ([]string, error) {
+	var warnings []string
+	known := func(title string) bool {
+		return slices.ContainsFunc(c.Types, func(t Type) bool { return t.Title == title })
+	}
+	for _, title := range c.TypeOrder {
+		if !known(title) {
+			warnings = append(warnings, fmt.Sprintf("typeOrder: %q is not a commit type", title))
+		}
+	}
+	for _, title := range c.HiddenTypes {
+		if !known(title) {
+			warnings = append(warnings, fmt.Sprintf("hiddenTypes: %q is not a commit type", title))
+		}
+	}
+	if len(c.HiddenTypes) > 0 && len(c.ListedTypes()) == 0 {
+		return nil, errors.New("hiddenTypes can't hide every commit type")
+	}
+	for _, t := range c.Templates {
+		if slices.Contains(c.HiddenTypes, t.Type) {
+			warnings = append(warnings, fmt.Sprintf("template %q uses hidden commit type %q", t.Name, t.Type))
+		}
+	}
+	return warnings, nil
+}
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"encoding/json"
+	"maps"
+	"reflect"
+	"slices"
+)
+
+// schemaRequired lists the keys each object in the schema must have, by
+// key path; "[]" stands for the items of a list.
+var schemaRequired = map[string][]string{
+	"types[]":     {"title"},
+	"templates[]": {"name"},
+}
+
+// themeNames returns the names of the built-in themes, sorted.
+func themeNames() []string {
+	return slices.Sorted(maps.Keys(builtinThemes))
+}
+
+// schemaEnums lists the values string settings accept, by key path.
+func schemaEnums() map[string][]string {
+	return map[string][]string{
+		"gitmoji":    {"", GitmojiShortcode, GitmojiEmoji},
+		"theme.name": append([]string{""}, themeNames()...),
+	}
+}
+
+// Schema returns a JSON schema of the config file that editors can
+// complete and check settings with. It is derived from Config, so it
+// accepts exactly the keys Load does.
+func Schema() ([]byte, error) {
+	schema := schemaFor(reflect.TypeOf(Config{}), "", schemaEnums())
+	schema["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	schema["title"] = "gocommit configuration"
+	schema["properties"].(map[string]any)[schemaKey] = map[string]any{"type": "string"}
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+// schemaFor returns the schema of the values decoded into t at path.
+func schemaFor(t reflect.Type, path string, enums map[string][]string) map[string]any {
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.String:
+		s := map[string]any{"type": "string"}
+		if values, ok := enums[path]; ok {
+			s["enum"] = values
+		}
+		return s
+	case reflect.Int:
+		// Every number in the config is a count or a limit.
+		return map[string]any{"type": "integer", "minimum": 0}
+	case reflect.Slice:
+		return map[string]any{"type": "array", "items": schemaFor(t.Elem(), path+"[]", enums)}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": schemaFor(t.Elem(), path+".*", enums)}
+	case reflect.Struct:
+		properties := make(map[string]any)
+		for name, field := range jsonFields(t) {
+			properties[name] = schemaFor(field, joinPath(path, name), enums)
+		}
+		s := map[string]any{"type": "object", "properties": properties, "additionalProperties": false}
+		if required, ok := schemaRequired[path]; ok {
+			s["required"] = required
+		}
+		return s
+	}
+	return map[string]any{}
+}
@@ -0,0 +1,28 @@
+package config
+
+import (
+	"regexp"
+	"strings"
+)
+
+// defaultBranchScopePattern strips a leading work-type folder, such as
+// feature/ or fix/, from the branch name.
+const defaultBranchScopePattern = `^(?:(?:feature|feat|fix|bugfix|hotfix|chore|refactor|docs|release)/)?(.+)$`
+
+// BranchScope derives a scope from a branch name: the first group pattern
+// captures, or its whole match, with slashes and whitespace replaced by
+// dashes. It returns "" when the pattern doesn't match.
+func BranchScope(pattern *regexp.Regexp, branch string) string {
+	match := pattern.FindStringSubmatch(branch)
+	if match == nil {
+		return ""
+	}
+	scope := match[0]
+	if len(match) > 1 {
+		scope = match[1]
+	}
+	scope = strings.Join(strings.FieldsFunc(scope, func(r rune) bool {
+		return r == '/' || r == ' ' || r == '\t'
+	}), "-")
+	return strings.Trim(scope, "-")
+}
@@ -0,0 +1,245 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// schemaKey may point editors at the JSON schema printed by gocommit
+// schema; it is the one top-level key that isn't a setting.
+const schemaKey = "$schema"
+
+// decodeLayer unmarshals a JSON config layer into c. Keys Config doesn't
+// define are errors, including ones that only differ in case, which
+// encoding/json would otherwise accept or drop silently. Malformed values
+// are reported by their key.
+func decodeLayer(data []byte, c *Config) error {
+	var probe any
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return describeJSONError(data, err)
+	}
+	if problems := unknownKeys(data, reflect.TypeOf(*c), ""); len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+	if err := json.Unmarshal(data, c); err != nil {
+		return describeJSONError(data, err)
+	}
+	return nil
+}
+
+// validateLayer rejects negative limits, which would otherwise be taken as
+// unset, and unknown theme names, which would only fail once the TUI starts.
+func validateLayer(c Config) error {
+	limits := []struct {
+		key string
+		n   int
+	}{
+		{"maxHeaderLength", c.MaxHeaderLength},
+		{"wrapBody", c.WrapBody},
+		{"maxChangedLines", c.MaxChangedLines},
+		{"idleTimeout", c.IdleTimeout},
+	}
+	for _, l := range limits {
+		if l.n < 0 {
+			return fmt.Errorf("%s: expected 0 or more, got %d", l.key, l.n)
+		}
+	}
+	if _, ok := builtinThemes[c.Theme.Name]; c.Theme.Name != "" && !ok {
+		return fmt.Errorf("theme.name: unknown theme %q (expected one of: %s)", c.Theme.Name, strings.Join(themeNames(), ", "))
+	}
+	return nil
+}
+
+// jsonFields maps the JSON names of the fields of struct type t to their
+// types.
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := range t.NumField() {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if !field.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = field.Type
+	}
+	return fields
+}
+
+// unknownKeys returns a problem for every key in raw, at path, that the Go
+// type t has no field for, looking into nested objects and lists. Values of
+// the wrong kind are left to json.Unmarshal to report.
+func unknownKeys(raw json.RawMessage, t reflect.Type, path string) []string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	var problems []string
+	switch t.Kind() {
+	case reflect.Struct:
+		var obj map[string]json.RawMessage
+		if json.Unmarshal(raw, &obj) != nil {
+			return nil
+		}
+		fields := jsonFields(t)
+		for _, key := range slices.Sorted(maps.Keys(obj)) {
+			field, ok := fields[key]
+			switch {
+			case ok:
+				problems = append(problems, unknownKeys(obj[key], field, joinPath(path, key))...)
+			case path == "" && key == schemaKey:
+			default:
+				problem := fmt.Sprintf("unknown key %q", joinPath(path, key))
+				if suggestion := closestKey(key, fields); suggestion != "" {
+					problem += fmt.Sprintf(" (did you mean %q?)", suggestion)
+				}
+				problems = append(problems, problem)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		var items []json.RawMessage
+		if json.Unmarshal(raw, &items) != nil {
+			return nil
+		}
+		for i, item := range items {
+			problems = append(problems, unknownKeys(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	case reflect.Map:
+		var obj map[string]json.RawMessage
+		if json.Unmarshal(raw, &obj) != nil {
+			return nil
+		}
+		for _, key := range slices.Sorted(maps.Keys(obj)) {
+			problems = append(problems, unknownKeys(obj[key], t.Elem(), joinPath(path, key))...)
+		}
+	}
+	return problems
+}
+
+// joinPath appends key to a dotted key path.
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// closestKey returns the field name key was most likely meant to be: one
+// differing only in case, or else the nearest within two edits. It returns
+// "" when nothing is close.
+func closestKey(key string, fields map[string]reflect.Type) string {
+	best, bestDistance := "", 3
+	for _, name := range slices.Sorted(maps.Keys(fields)) {
+		if strings.EqualFold(name, key) {
+			return name
+		}
+		if d := editDistance(strings.ToLower(key), strings.ToLower(name)); d < bestDistance {
+			best, bestDistance = name, d
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := range ra {
+		cur := make([]int, len(rb)+1)
+		cur[0] = i + 1
+		for j := range rb {
+			cost := 1
+			if ra[i] == rb[j] {
+				cost = 0
+			}
+			cur[j+1] = min(prev[j+1]+1, cur[j]+1, prev[j]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(rb)]
+}
+
+// jsonKinds names the JSON values encoding/json reports in type errors.
+var jsonKinds = map[string]string{
+	"bool":   "true or false",
+	"string": "a string",
+	"number": "a number",
+	"array":  "a list",
+	"object": "an object",
+}
+
+// kindOf describes the JSON value a Go type is decoded from.
+func kindOf(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Bool:
+		return jsonKinds["bool"]
+	case reflect.String:
+		return jsonKinds["string"]
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "a whole number"
+	case reflect.Float32, reflect.Float64:
+		return jsonKinds["number"]
+	case reflect.Slice, reflect.Array:
+		return jsonKinds["array"]
+	}
+	return jsonKinds["object"]
+}
+
+// describeJSONError rewrites a decoding error to point at the line and
+// column of a syntax error, or name the key holding a value of the wrong
+// kind, such as "types[1].title: expected a string, got a number".
+func describeJSONError(data []byte, err error) error {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		line, column := position(data, syntaxErr.Offset)
+		return fmt.Errorf("invalid JSON at line %d, column %d: %s", line, column, syntaxErr)
+	case errors.As(err, &typeErr):
+		got, _, _ := strings.Cut(typeErr.Value, " ")
+		if kind, ok := jsonKinds[got]; ok {
+			got = kind
+		}
+		if typeErr.Field == "" {
+			return fmt.Errorf("expected an object of settings, got %s", got)
+		}
+		return fmt.Errorf("%s: expected %s, got %s", fieldPath(typeErr.Field), kindOf(typeErr.Type), got)
+	}
+	return fmt.Errorf("parsing: %w", err)
+}
+
+// fieldPath turns the dotted field of a type error, such as
+// "types.1.title", into "types[1].title".
+func fieldPath(field string) string {
+	var b strings.Builder
+	for i, part := range strings.Split(field, ".") {
+		switch {
+		case part != "" && strings.Trim(part, "0123456789") == "":
+			b.WriteString("[" + part + "]")
+		case i > 0:
+			b.WriteString("." + part)
+		default:
+			b.WriteString(part)
+		}
+	}
+	return b.String()
+}
+
+// position returns the 1-based line and column of offset in data.
+func position(data []byte, offset int64) (line, column int) {
+	before := data[:min(int(offset), len(data))]
+	line = bytes.Count(before, []byte("\n")) + 1
+	column = len(before) - bytes.LastIndexByte(before, '\n')
+	return line, column
+}
@@ -0,0 +1,41 @@
+package config
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// SubjectRules are optional fixes applied to the subject before committing.
+type SubjectRules struct {
+	StripPeriod bool
+	Lowercase   bool
+	// CollapseWhitespace turns runs of spaces inside the subject into one.
+	CollapseWhitespace bool
+}
+
+// SubjectRules returns the subject normalization rules from the config.
+func (c Config) SubjectRules() SubjectRules {
+	return SubjectRules{
+		StripPeriod:        c.StripPeriod,
+		Lowercase:          c.LowercaseSubject,
+		CollapseWhitespace: c.CollapseWhitespace,
+	}
+}
+
+// Normalize applies the rules to subject. Leading and trailing whitespace
+// is always trimmed.
+func (r SubjectRules) Normalize(subject string) string {
+	subject = strings.TrimSpace(subject)
+	if r.CollapseWhitespace {
+		subject = strings.Join(strings.Fields(subject), " ")
+	}
+	if r.StripPeriod {
+		subject = strings.TrimSpace(strings.TrimRight(subject, "."))
+	}
+	if r.Lowercase && subject != "" {
+		first, size := utf8.DecodeRuneInString(subject)
+		subject = string(unicode.ToLower(first)) + subject[size:]
+	}
+	return subject
+}
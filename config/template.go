@@ -0,0 +1,39 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Template is a named starting point for a commit. Empty fields are
+// left for the user to fill in.
+type Template struct {
+	Name    string   `json:"name"`
+	Type    string   `json:"type"`
+	Scope   string   `json:"scope"`
+	Subject string   `json:"subject"`
+	Body    string   `json:"body"`
+	Footers []string `json:"footers"`
+}
+
+// validateTemplates rejects unnamed templates and warns about templates
+// referring to commit types that aren't configured.
+func validateTemplates(templates []Template, types []Type) ([]string, error) {
+	var warnings []string
+	for i, t := range templates {
+		if strings.TrimSpace(t.Name) == "" {
+			return nil, fmt.Errorf("template %d has an empty name", i+1)
+		}
+		if t.Type == "" {
+			continue
+		}
+		known := false
+		for _, ct := range types {
+			known = known || ct.Title == t.Type
+		}
+		if !known {
+			warnings = append(warnings, fmt.Sprintf("template %q uses unknown commit type %q", t.Name, t.Type))
+		}
+	}
+	return warnings, nil
+}
@@ -0,0 +1,70 @@
+package config
+
+import "fmt"
+
+// Theme sets the colors used by the TUI. Name selects a built-in
+// theme; any other field that is set overrides the chosen theme.
+type Theme struct {
+	Name            string `json:"name"`
+	TitleForeground string `json:"titleForeground"`
+	TitleBackground string `json:"titleBackground"`
+	Item            string `json:"item"`
+	Hint            string `json:"hint"`
+	Selected        string `json:"selected"`
+	SelectedDesc    string `json:"selectedDesc"`
+}
+
+// defaultTheme matches the original hardcoded colors.
+var defaultTheme = Theme{
+	TitleForeground: "#FFFDF5",
+	TitleBackground: "#25A065",
+	Hint:            "#888888",
+	Selected:        "170",
+	SelectedDesc:    "240",
+}
+
+var builtinThemes = map[string]Theme{
+	"default": defaultTheme,
+	"dracula": {
+		TitleForeground: "#282A36",
+		TitleBackground: "#BD93F9",
+		Item:            "#F8F8F2",
+		Hint:            "#6272A4",
+		Selected:        "#FF79C6",
+		SelectedDesc:    "#8BE9FD",
+	},
+	"solarized": {
+		TitleForeground: "#FDF6E3",
+		TitleBackground: "#268BD2",
+		Item:            "#839496",
+		Hint:            "#586E75",
+		Selected:        "#B58900",
+		SelectedDesc:    "#2AA198",
+	},
+}
+
+// Resolve returns the built-in theme named by t with t's own colors layered
+// on top.
+func (t Theme) Resolve() (Theme, error) {
+	base := defaultTheme
+	if t.Name != "" {
+		builtin, ok := builtinThemes[t.Name]
+		if !ok {
+			return base, fmt.Errorf("unknown theme %q", t.Name)
+		}
+		base = builtin
+	}
+
+	override := func(dst *string, src string) {
+		if src != "" {
+			*dst = src
+		}
+	}
+	override(&base.TitleForeground, t.TitleForeground)
+	override(&base.TitleBackground, t.TitleBackground)
+	override(&base.Item, t.Item)
+	override(&base.Hint, t.Hint)
+	override(&base.Selected, t.Selected)
+	override(&base.SelectedDesc, t.SelectedDesc)
+	return base, nil
+}
@@ -0,0 +1,573 @@
+// Package git runs the git commands gocommit needs. Commands go through a
+// GitRunner so the commit logic can be exercised without a real repository.
+package git
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GitRunner runs git with the given arguments.
+type GitRunner interface {
+	// Output returns the command's standard output.
+	Output(args ...string) ([]byte, error)
+	// CombinedOutput returns standard output and standard error together.
+	CombinedOutput(args ...string) ([]byte, error)
+	// Stream copies standard output and standard error to w as the command
+	// runs.
+	Stream(w io.Writer, args ...string) error
+}
+
+// ExecRunner is the GitRunner that runs the git binary on the PATH.
+type ExecRunner struct {
+	// Dir is the directory git runs in; empty means the current one.
+	Dir string
+}
+
+// command returns the git command for args, run in r.Dir.
+func (r ExecRunner) command(args ...string) *exec.Cmd {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = r.Dir
+	return cmd
+}
+
+func (r ExecRunner) Output(args ...string) ([]byte, error) {
+	return r.command(args...).Output()
+}
+
+func (r ExecRunner) CombinedOutput(args ...string) ([]byte, error) {
+	return r.command(args...).CombinedOutput()
+}
+
+func (r ExecRunner) Stream(w io.Writer, args ...string) error {
+	cmd := r.command(args...)
+	cmd.Stdout = w
+	cmd.Stderr = w
+	return cmd.Run()
+}
+
+// Repo is the repository in the current directory.
+type Repo struct {
+	runner GitRunner
+}
+
+// NewRepo returns a Repo that runs git through runner.
+func NewRepo(runner GitRunner) *Repo {
+	return &Repo{runner: runner}
+}
+
+// output runs git and returns its trimmed standard output.
+func (r *Repo) output(args ...string) (string, error) {
+	out, err := r.runner.Output(args...)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// ErrNotGitRepo is returned when gocommit runs outside a git work tree.
+var ErrNotGitRepo = errors.New("not a git repository (run gocommit inside a git work tree)")
+
+// Check makes sure the current directory is inside a git work tree.
+func (r *Repo) Check() error {
+	out, err := r.output("rev-parse", "--is-inside-work-tree")
+	if err != nil || out != "true" {
+		return ErrNotGitRepo
+	}
+	return nil
+}
+
+// Root returns the top-level directory of the repository.
+func (r *Repo) Root() (string, error) {
+	return r.output("rev-parse", "--show-toplevel")
+}
+
+// CurrentBranch returns the checked out branch, or "HEAD" when detached.
+func (r *Repo) CurrentBranch() (string, error) {
+	return r.output("rev-parse", "--abbrev-ref", "HEAD")
+}
+
+// Detached reports whether HEAD points at a commit rather than a branch, in
+// which case new commits aren't on any branch.
+func (r *Repo) Detached() bool {
+	_, err := r.runner.Output("symbolic-ref", "-q", "HEAD")
+	return err != nil
+}
+
+// gitDir returns the absolute path of the .git directory. Git prints a
+// relative one for --git-dir, which would be resolved against gocommit's
+// working directory rather than the one git runs in.
+func (r *Repo) gitDir() (string, error) {
+	return r.output("rev-parse", "--absolute-git-dir")
+}
+
+// InProgress returns the operation, such as "merge" or "rebase", that is
+// stopped waiting for a commit, or "" when there is none.
+func (r *Repo) InProgress() string {
+	dir, err := r.gitDir()
+	if err != nil {
+		return ""
+	}
+	for _, op := range []struct{ file, name string }{
+		{"MERGE_HEAD", "merge"},
+		{"rebase-merge", "rebase"},
+		{"rebase-apply", "rebase"},
+		{"CHERRY_PICK_HEAD", "cherry-pick"},
+		{"REVERT_HEAD", "revert"},
+	} {
+		if _, err := os.Stat(filepath.Join(dir, op.file)); err == nil {
+			return op.name
+		}
+	}
+	return ""
+}
+
+// MergeMessage returns the message git prepared for an in-progress merge,
+// without its comment lines.
+func (r *Repo) MergeMessage() (string, error) {
+	dir, err := r.gitDir()
+	if err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "MERGE_MSG"))
+	if err != nil {
+		return "", err
+	}
+	var lines []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n")), nil
+}
+
+// StagedFiles returns the paths of the staged files.
+func (r *Repo) StagedFiles() ([]string, error) {
+	out, err := r.output("diff", "--name-only", "--cached")
+	if err != nil {
+		return nil, err
+	}
+	if out == "" {
+		return []string{}, nil
+	}
+	return strings.Split(out, "\n"), nil
+}
+
+// FileStat is the number of changed lines of a staged file.
+type FileStat struct {
+	Path    string
+	Added   int
+	Deleted int
+	Binary  bool
+}
+
+// StagedStats returns the line counts of the staged files, as reported by
+// git diff --numstat. Renamed files are listed under their new path.
+func (r *Repo) StagedStats() ([]FileStat, error) {
+	out, err := r.runner.Output("diff", "--cached", "--numstat", "-z")
+	if err != nil {
+		return nil, err
+	}
+
+	var stats []FileStat
+	fields := strings.Split(string(out), "\x00")
+	for i := 0; i < len(fields); i++ {
+		parts := strings.SplitN(fields[i], "\t", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		stat := FileStat{Path: parts[2]}
+		if parts[2] == "" && i+2 < len(fields) {
+			// A rename: the old and new paths follow as separate fields.
+			stat.Path = fields[i+2]
+			i += 2
+		}
+		if parts[0] == "-" {
+			stat.Binary = true
+		} else {
+			stat.Added, _ = strconv.Atoi(parts[0])
+			stat.Deleted, _ = strconv.Atoi(parts[1])
+		}
+		stats = append(stats, stat)
+	}
+	return stats, nil
+}
+
+// UnstagedFiles returns the paths of tracked files with changes that are not
+// staged.
+func (r *Repo) UnstagedFiles() ([]string, error) {
+	out, err := r.output("diff", "--name-only")
+	if err != nil {
+		return nil, err
+	}
+	if out == "" {
+		return []string{}, nil
+	}
+	return strings.Split(out, "\n"), nil
+}
+
+// UnstagedChange is a file git status lists as not staged for commit.
+type UnstagedChange struct {
+	Path string
+	// Untracked is set for new files git doesn't know about yet.
+	Untracked bool
+}
+
+// UnstagedChanges returns the changes that a commit would leave behind:
+// tracked files modified or deleted in the work tree, and untracked files,
+// as listed by git status --porcelain.
+func (r *Repo) UnstagedChanges() ([]UnstagedChange, error) {
+	out, err := r.runner.Output("status", "--porcelain", "-z")
+	if err != nil {
+		return nil, err
+	}
+	var changes []UnstagedChange
+	entries := strings.Split(string(out), "\x00")
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+		if len(entry) < 4 {
+			continue
+		}
+		x, y, path := entry[0], entry[1], entry[3:]
+		if x == 'R' || x == 'C' {
+			// The original path of a rename follows as its own entry.
+			i++
+		}
+		switch {
+		case x == '?':
+			changes = append(changes, UnstagedChange{Path: path, Untracked: true})
+		case y != ' ' && y != '!':
+			changes = append(changes, UnstagedChange{Path: path})
+		}
+	}
+	return changes, nil
+}
+
+// StageTracked stages every modification and deletion of tracked files in
+// the work tree (git add -u). Untracked files are not added.
+func (r *Repo) StageTracked() error {
+	if output, err := r.runner.CombinedOutput("add", "--update"); err != nil {
+		if msg := strings.TrimSpace(string(output)); msg != "" {
+			return errors.New(msg)
+		}
+		return err
+	}
+	return nil
+}
+
+// StagedDiff returns the raw staged diff.
+func (r *Repo) StagedDiff() (string, error) {
+	out, err := r.runner.Output("diff", "--cached")
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// HeadMessage returns the full message of the HEAD commit.
+func (r *Repo) HeadMessage() (string, error) {
+	out, err := r.runner.Output("log", "-1", "--pretty=%B")
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// Identity returns the committer from git config as "Name <email>", the
+// form used in Signed-off-by trailers.
+func (r *Repo) Identity() (string, error) {
+	name, err := r.output("config", "user.name")
+	if err != nil {
+		return "", err
+	}
+	email, err := r.output("config", "user.email")
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s <%s>", name, email), nil
+}
+
+// HeadSHA returns the full hash of the HEAD commit.
+func (r *Repo) HeadSHA() (string, error) {
+	return r.output("rev-parse", "HEAD")
+}
+
+// UndoCommit moves the branch back to the parent of commit sha, keeping its
+// changes staged. It refuses when HEAD has moved on from sha.
+func (r *Repo) UndoCommit(sha string) error {
+	head, err := r.HeadSHA()
+	if err != nil {
+		return err
+	}
+	if head != sha {
+		return errors.New("HEAD has moved since the commit was made")
+	}
+	if output, err := r.runner.CombinedOutput("reset", "--soft", sha+"~1"); err != nil {
+		if msg := strings.TrimSpace(string(output)); msg != "" {
+			return errors.New(msg)
+		}
+		return err
+	}
+	return nil
+}
+
+// LastSubject returns the subject line of the HEAD commit.
+func (r *Repo) LastSubject() (string, error) {
+	return r.output("log", "-1", "--pretty=%s")
+}
+
+// LogEntry is a commit listed by RecentCommits.
+type LogEntry struct {
+	SHA     string
+	Subject string
+}
+
+// RecentCommits returns up to n commits reachable from HEAD, newest first.
+func (r *Repo) RecentCommits(n int) ([]LogEntry, error) {
+	out, err := r.output("log", fmt.Sprintf("-%d", n), "--pretty=format:%H%x1f%s")
+	if err != nil {
+		return nil, err
+	}
+	var entries []LogEntry
+	for _, line := range strings.Split(out, "\n") {
+		if sha, subject, ok := strings.Cut(line, "\x1f"); ok {
+			entries = append(entries, LogEntry{SHA: sha, Subject: subject})
+		}
+	}
+	return entries, nil
+}
+
+// ResolveCommit returns the full hash of the commit rev names, such as a
+// short hash or HEAD~2.
+func (r *Repo) ResolveCommit(rev string) (string, error) {
+	sha, err := r.output("rev-parse", "--verify", "--quiet", rev+"^{commit}")
+	if err != nil || sha == "" {
+		return "", fmt.Errorf("%q is not a commit in this repository", rev)
+	}
+	return sha, nil
+}
+
+// Revert stages the changes that undo commit sha and leaves the revert in
+// progress, for the next commit to conclude (git revert --no-commit).
+func (r *Repo) Revert(sha string) error {
+	if output, err := r.runner.CombinedOutput("revert", "--no-commit", sha); err != nil {
+		if msg := strings.TrimSpace(string(output)); msg != "" {
+			return errors.New(msg)
+		}
+		return err
+	}
+	return nil
+}
+
+// RevertHead returns the hash of the commit an in-progress revert undoes.
+func (r *Repo) RevertHead() (string, error) {
+	return r.output("rev-parse", "--verify", "--quiet", "REVERT_HEAD")
+}
+
+// RevertMessage returns the conventional message for reverting commit sha:
+// "revert: <its subject>" with a "This reverts commit <sha>." body.
+func (r *Repo) RevertMessage(sha string) (Message, error) {
+	subject, err := r.output("log", "-1", "--pretty=%s", sha)
+	if err != nil {
+		return Message{}, err
+	}
+	return Message{
+		Type:    "revert",
+		Subject: subject,
+		Body:    fmt.Sprintf("This reverts commit %s.", sha),
+	}, nil
+}
+
+// dateLayouts are the formats ParseDate accepts. Dates without a time zone
+// are local.
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	time.RFC1123Z,
+}
+
+// ParseDate parses a commit date such as "2024-05-01", "2024-05-01 15:04" or
+// "2024-05-01T15:04:05+02:00". Git's own parser guesses at almost anything,
+// so dates are checked here to catch typos before committing.
+func ParseDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q (expected a date such as 2024-05-01, 2024-05-01 15:04 or 2024-05-01T15:04:05+02:00)", value)
+}
+
+// CommitOptions are the git commit flags that don't affect the message.
+type CommitOptions struct {
+	Sign       bool
+	SigningKey string
+	Amend      bool
+	NoVerify   bool
+	AllowEmpty bool
+	// Author overrides the commit author, as "Name <email>".
+	Author string
+	// Signoff adds a Signed-off-by trailer for the committer (git commit -s).
+	Signoff bool
+	// Date overrides the author date, in a format from ParseDate.
+	Date string
+	// DryRun prints the message instead of running git commit.
+	DryRun bool
+	// ViaFile writes the message to a temporary file and commits with
+	// -F instead of passing each paragraph with -m.
+	ViaFile bool
+	// Paths limits the commit to these files (git commit -- <paths>),
+	// given relative to the top of the work tree. Git commits their working
+	// tree contents, and other staged files stay staged.
+	Paths []string
+}
+
+// args returns the git commit arguments for the options.
+func (o CommitOptions) args() []string {
+	var args []string
+	if o.Sign {
+		args = append(args, "-S"+o.SigningKey)
+	}
+	if o.Amend {
+		args = append(args, "--amend")
+	}
+	if o.NoVerify {
+		args = append(args, "--no-verify")
+	}
+	if o.AllowEmpty {
+		args = append(args, "--allow-empty")
+	}
+	if o.Author != "" {
+		args = append(args, "--author="+o.Author)
+	}
+	if o.Signoff {
+		args = append(args, "--signoff")
+	}
+	if o.Date != "" {
+		args = append(args, "--date="+o.Date)
+	}
+	return args
+}
+
+// CommitArgs returns the full git arguments used to commit msg. With
+// ViaFile set the message is read from file.
+func CommitArgs(msg Message, opts CommitOptions, file string) []string {
+	args := append([]string{"commit"}, opts.args()...)
+	if opts.ViaFile {
+		args = append(args, "-F", file)
+	} else {
+		for _, p := range msg.Paragraphs() {
+			args = append(args, "-m", p)
+		}
+	}
+	if len(opts.Paths) > 0 {
+		args = append(args, "--")
+		for _, p := range opts.Paths {
+			// The paths are relative to the top of the work tree, which
+			// git may not be running in.
+			args = append(args, ":(top)"+p)
+		}
+	}
+	return args
+}
+
+// AmendArgs returns the git arguments that reopen the HEAD commit's message
+// in the editor (git commit --amend), signing and skipping hooks as opts
+// did for the original commit.
+func AmendArgs(opts CommitOptions) []string {
+	args := []string{"commit", "--amend"}
+	if opts.Sign {
+		args = append(args, "-S"+opts.SigningKey)
+	}
+	if opts.NoVerify {
+		args = append(args, "--no-verify")
+	}
+	return args
+}
+
+// CommandLine returns the git commit command for msg as it would be typed in
+// a shell. The signing key is masked.
+func CommandLine(msg Message, opts CommitOptions) string {
+	if opts.SigningKey != "" {
+		opts.SigningKey = "****"
+	}
+	words := []string{"git"}
+	for _, arg := range CommitArgs(msg, opts, "<message file>") {
+		words = append(words, shellQuote(arg))
+	}
+	return strings.Join(words, " ")
+}
+
+// shellQuote quotes s for a POSIX shell when it contains anything but
+// plainly safe characters.
+func shellQuote(s string) string {
+	safe := s != "" && strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_=./:@+,", r))
+	}) == -1
+	if safe {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// Committer creates commits. *Repo is the real implementation; callers
+// hold a Committer so a fake can record the message and options instead.
+type Committer interface {
+	Commit(msg Message, opts CommitOptions) (string, error)
+}
+
+// Commit runs git commit and returns its combined output so hook messages
+// and other failures can be shown to the user.
+func (r *Repo) Commit(msg Message, opts CommitOptions) (string, error) {
+	var file string
+	if opts.ViaFile {
+		f, err := os.CreateTemp("", "gocommit-*.txt")
+		if err != nil {
+			return "", err
+		}
+		defer os.Remove(f.Name())
+		_, err = f.WriteString(msg.String() + "\n")
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return "", err
+		}
+		file = f.Name()
+	}
+
+	output, err := r.runner.CombinedOutput(CommitArgs(msg, opts, file)...)
+	return strings.TrimSpace(string(output)), err
+}
+
+// HasUpstream reports whether the current branch tracks a remote branch.
+func (r *Repo) HasUpstream() bool {
+	_, err := r.runner.Output("rev-parse", "--abbrev-ref", "--symbolic-full-name", "@{u}")
+	return err == nil
+}
+
+// pushArgs returns the git push arguments, setting the upstream to
+// origin/<branch> when requested.
+func pushArgs(setUpstream bool, branch string) []string {
+	if setUpstream {
+		return []string{"push", "-u", "origin", branch}
+	}
+	return []string{"push"}
+}
+
+// Push runs git push, writing its progress output to w.
+func (r *Repo) Push(w io.Writer, setUpstream bool, branch string) error {
+	return r.runner.Stream(w, pushArgs(setUpstream, branch)...)
+}
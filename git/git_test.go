@@ -0,0 +1,285 @@
+package git
+
+import (
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// fakeRunner is a GitRunner that records the arguments of every command and
+// answers with canned output.
+type fakeRunner struct {
+	calls  [][]string
+	output string
+	err    error
+}
+
+func (f *fakeRunner) Output(args ...string) ([]byte, error) {
+	f.calls = append(f.calls, args)
+	return []byte(f.output), f.err
+}
+
+func (f *fakeRunner) CombinedOutput(args ...string) ([]byte, error) {
+	f.calls = append(f.calls, args)
+	return []byte(f.output), f.err
+}
+
+func (f *fakeRunner) Stream(w io.Writer, args ...string) error {
+	f.calls = append(f.calls, args)
+	_, _ = io.WriteString(w, f.output)
+	return f.err
+}
+
+var _ Committer = (*Repo)(nil)
+
+func TestCommitArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  Message
+		opts CommitOptions
+		want []string
+	}{
+		{
+			name: "type only",
+			msg:  Message{Type: "feat", Subject: "add login"},
+			want: []string{"commit", "-m", "feat: add login"},
+		},
+		{
+			name: "scope",
+			msg:  Message{Type: "fix", Scope: "api", Subject: "handle timeouts"},
+			want: []string{"commit", "-m", "fix(api): handle timeouts"},
+		},
+		{
+			name: "emoji",
+			msg:  Message{Emoji: "✨ ", Type: "feat", Scope: "ui", Subject: "add theme"},
+			want: []string{"commit", "-m", "✨ feat(ui): add theme"},
+		},
+		{
+			name: "no emoji",
+			msg:  Message{Type: "feat", Scope: "ui", Subject: "add theme"},
+			want: []string{"commit", "-m", "feat(ui): add theme"},
+		},
+		{
+			name: "gitmoji",
+			msg:  Message{Emoji: "✨ ", Gitmoji: ":sparkles:", Type: "feat", Scope: "ui", Subject: "add theme"},
+			want: []string{"commit", "-m", ":sparkles: (ui): add theme"},
+		},
+		{
+			name: "breaking marker",
+			msg:  Message{Type: "feat", Scope: "api", Subject: "drop v1", Breaking: true},
+			want: []string{"commit", "-m", "feat(api)!: drop v1"},
+		},
+		{
+			name: "breaking change footer",
+			msg:  Message{Type: "feat", Subject: "drop v1", Breaking: true, BreakingDesc: "v1 endpoints are gone"},
+			want: []string{"commit", "-m", "feat!: drop v1", "-m", "BREAKING CHANGE: v1 endpoints are gone"},
+		},
+		{
+			name: "gitmoji breaking",
+			msg:  Message{Gitmoji: ":sparkles:", Type: "feat", Subject: "drop v1 api", Breaking: true},
+			want: []string{"commit", "-m", ":sparkles: drop v1 api", "-m", "BREAKING CHANGE: drop v1 api"},
+		},
+		{
+			name: "breaking without a breaking placeholder",
+			msg:  Message{Type: "feat", Subject: "drop v1", Breaking: true, HeaderFormat: "[{type}] {subject}"},
+			want: []string{"commit", "-m", "[feat] drop v1", "-m", "BREAKING CHANGE: drop v1"},
+		},
+		{
+			name: "body and footers",
+			msg: Message{
+				Type:      "fix",
+				Subject:   "retry uploads",
+				Body:      "Uploads failed on flaky networks.",
+				Coauthors: []string{"Jane Doe <jane@example.com>"},
+				Refs:      []string{"Closes #12"},
+				Trailers:  []string{"Reviewed-by: Sam Roe <sam@example.com>"},
+			},
+			want: []string{
+				"commit",
+				"-m", "fix: retry uploads",
+				"-m", "Uploads failed on flaky networks.",
+				"-m", "Co-authored-by: Jane Doe <jane@example.com>\nCloses #12\nReviewed-by: Sam Roe <sam@example.com>",
+			},
+		},
+		{
+			name: "ticket",
+			msg:  Message{Type: "fix", Ticket: "ABC-12", Subject: "retry uploads"},
+			want: []string{"commit", "-m", "fix: ABC-12 retry uploads"},
+		},
+		{
+			name: "flags",
+			msg:  Message{Type: "chore", Subject: "bump deps"},
+			opts: CommitOptions{
+				Sign: true, SigningKey: "ABCD", Amend: true, NoVerify: true, AllowEmpty: true,
+				Author: "Jane Doe <jane@example.com>", Signoff: true, Date: "2024-05-01",
+			},
+			want: []string{
+				"commit", "-SABCD", "--amend", "--no-verify", "--allow-empty",
+				"--author=Jane Doe <jane@example.com>", "--signoff", "--date=2024-05-01",
+				"-m", "chore: bump deps",
+			},
+		},
+		{
+			name: "paths",
+			msg:  Message{Type: "docs", Subject: "fix typo"},
+			opts: CommitOptions{Paths: []string{"README.md", "docs/guide.md"}},
+			want: []string{"commit", "-m", "docs: fix typo", "--", ":(top)README.md", ":(top)docs/guide.md"},
+		},
+		{
+			name: "via file",
+			msg:  Message{Type: "docs", Subject: "fix typo", Body: "Details."},
+			opts: CommitOptions{ViaFile: true, Paths: []string{"README.md"}},
+			want: []string{"commit", "-F", "msg.txt", "--", ":(top)README.md"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CommitArgs(tt.msg, tt.opts, "msg.txt"); !slices.Equal(got, tt.want) {
+				t.Errorf("CommitArgs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHeader(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  Message
+		want string
+	}{
+		{"type", Message{Type: "feat", Subject: "add login"}, "feat: add login"},
+		{"scope", Message{Type: "feat", Scope: "auth", Subject: "add login"}, "feat(auth): add login"},
+		{"emoji", Message{Emoji: "🐛 ", Type: "fix", Subject: "stop crash"}, "🐛 fix: stop crash"},
+		{"breaking", Message{Type: "feat", Scope: "api", Subject: "drop v1", Breaking: true}, "feat(api)!: drop v1"},
+		{"free-form", Message{Subject: "Merge branch 'main'"}, "Merge branch 'main'"},
+		{"gitmoji", Message{Gitmoji: ":bug:", Type: "fix", Subject: "stop crash"}, ":bug: stop crash"},
+		{"gitmoji scope", Message{Gitmoji: "🐛", Type: "fix", Scope: "ui", Subject: "stop crash"}, "🐛 (ui): stop crash"},
+		{"header format", Message{Type: "fix", Scope: "ui", Subject: "stop crash", HeaderFormat: "{type}/{scope}: {subject}"}, "fix/ui: stop crash"},
+		{"header format without scope", Message{Type: "fix", Subject: "stop crash", HeaderFormat: "{type}/{scope}: {subject}"}, "fix: stop crash"},
+		{"header format with a leading scope", Message{Type: "fix", Subject: "stop crash", HeaderFormat: "[{scope}] {subject}"}, "stop crash"},
+		{"header format with a spaced scope", Message{Type: "fix", Subject: "stop crash", HeaderFormat: "{type} ({scope}): {subject}"}, "fix: stop crash"},
+		{"header format with a scope between words", Message{Type: "fix", Subject: "stop crash", HeaderFormat: "{type} [{scope}] {subject}"}, "fix stop crash"},
+		{"header format with a trailing scope", Message{Type: "fix", Subject: "stop crash", HeaderFormat: "{type}: {subject} ({scope})"}, "fix: stop crash"},
+		{"header format breaking", Message{Type: "fix", Subject: "stop crash", Breaking: true, HeaderFormat: "{type}{breaking}: {subject}"}, "fix!: stop crash"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.msg.Header(); got != tt.want {
+				t.Errorf("Header() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParagraphs(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  Message
+		want []string
+	}{
+		{
+			name: "header only",
+			msg:  Message{Type: "feat", Subject: "add login"},
+			want: []string{"feat: add login"},
+		},
+		{
+			name: "body",
+			msg:  Message{Type: "feat", Subject: "add login", Body: "First line.\n\nSecond paragraph."},
+			want: []string{"feat: add login", "First line.\n\nSecond paragraph."},
+		},
+		{
+			name: "footers share the last paragraph",
+			msg: Message{
+				Type:         "feat",
+				Subject:      "add login",
+				Breaking:     true,
+				BreakingDesc: "sessions are reset",
+				Coauthors:    []string{"Jane Doe <jane@example.com>", "Sam Roe <sam@example.com>"},
+				Refs:         []string{"Refs: JIRA-456"},
+				Trailers:     []string{"Acked-by: Kim Poe <kim@example.com>"},
+			},
+			want: []string{
+				"feat!: add login",
+				"BREAKING CHANGE: sessions are reset\nCo-authored-by: Jane Doe <jane@example.com>\nCo-authored-by: Sam Roe <sam@example.com>\nRefs: JIRA-456\nAcked-by: Kim Poe <kim@example.com>",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.msg.Paragraphs(); !slices.Equal(got, tt.want) {
+				t.Errorf("Paragraphs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRepoCommit(t *testing.T) {
+	runner := &fakeRunner{output: "[main abc1234] feat(api): add endpoint\n"}
+	repo := NewRepo(runner)
+	msg := Message{Type: "feat", Scope: "api", Subject: "add endpoint", Refs: []string{"Closes #3"}}
+
+	output, err := repo.Commit(msg, CommitOptions{NoVerify: true, Paths: []string{"api.go"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "[main abc1234] feat(api): add endpoint"; output != want {
+		t.Errorf("output = %q, want %q", output, want)
+	}
+	want := [][]string{{"commit", "--no-verify", "-m", "feat(api): add endpoint", "-m", "Closes #3", "--", ":(top)api.go"}}
+	if !slices.EqualFunc(runner.calls, want, slices.Equal) {
+		t.Errorf("git ran %q, want %q", runner.calls, want)
+	}
+}
+
+func TestRepoCommitViaFile(t *testing.T) {
+	var written string
+	runner := &fakeRunner{}
+	repo := NewRepo(recordFile{runner, &written})
+	msg := Message{Type: "fix", Subject: "stop crash", Body: "It crashed."}
+
+	if _, err := repo.Commit(msg, CommitOptions{ViaFile: true}); err != nil {
+		t.Fatal(err)
+	}
+	if len(runner.calls) != 1 {
+		t.Fatalf("git ran %d commands, want 1", len(runner.calls))
+	}
+	args := runner.calls[0]
+	if len(args) != 3 || args[0] != "commit" || args[1] != "-F" {
+		t.Fatalf("git ran %q, want commit -F <file>", args)
+	}
+	if want := "fix: stop crash\n\nIt crashed.\n"; written != want {
+		t.Errorf("message file = %q, want %q", written, want)
+	}
+}
+
+// recordFile reads the message file passed to git commit -F before it is
+// removed.
+type recordFile struct {
+	*fakeRunner
+	contents *string
+}
+
+func (r recordFile) CombinedOutput(args ...string) ([]byte, error) {
+	if i := slices.Index(args, "-F"); i >= 0 && i+1 < len(args) {
+		data, err := os.ReadFile(args[i+1])
+		if err != nil {
+			return nil, err
+		}
+		*r.contents = string(data)
+	}
+	return r.fakeRunner.CombinedOutput(args...)
+}
+
+func TestCommandLine(t *testing.T) {
+	msg := Message{Type: "feat", Subject: "don't panic"}
+	got := CommandLine(msg, CommitOptions{Sign: true, SigningKey: "secret"})
+	want := `git commit '-S****' -m 'feat: don'\''t panic'`
+	if got != want {
+		t.Errorf("CommandLine() = %s, want %s", got, want)
+	}
+	if strings.Contains(got, "secret") {
+		t.Error("CommandLine() shows the signing key")
+	}
+}
@@ -0,0 +1,373 @@
+package git
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Message holds the parts of a conventional commit message.
+type Message struct {
+	Emoji        string
+	Type         string
+	Scope        string
+	Ticket       string
+	Subject      string
+	Body         string
+	Breaking     bool
+	BreakingDesc string
+	Coauthors    []string
+	Refs         []string
+	Trailers     []string
+	// Gitmoji, when set, replaces the type prefix with a gitmoji such as
+	// ":sparkles:".
+	Gitmoji string
+	// HeaderFormat, when set, lays out the header instead of the
+	// conventional format; see FormatHeader.
+	HeaderFormat string
+}
+
+// Header builds the conventional commit header, adding the scope in
+// parentheses only when one was given and a "!" for breaking changes. A
+// message without a type is free-form and uses the subject as the header.
+// Gitmoji headers take the form "<gitmoji> (scope): subject".
+func (m Message) Header() string {
+	subject := m.Subject
+	if m.Ticket != "" {
+		subject = m.Ticket + " " + subject
+	}
+	if m.Type == "" {
+		return subject
+	}
+	if m.Gitmoji != "" {
+		if m.Scope != "" {
+			return fmt.Sprintf("%s (%s): %s", m.Gitmoji, m.Scope, subject)
+		}
+		return m.Gitmoji + " " + subject
+	}
+	if m.HeaderFormat != "" {
+		return FormatHeader(m.HeaderFormat, m.Emoji, m.Type, m.Scope, m.Breaking, subject)
+	}
+	prefix := m.Emoji + m.Type
+	if m.Scope != "" {
+		prefix += "(" + m.Scope + ")"
+	}
+	if m.Breaking {
+		prefix += "!"
+	}
+	return fmt.Sprintf("%s: %s", prefix, subject)
+}
+
+// scopeBrackets are the pairs that are dropped along with an empty
+// {scope}.
+var scopeBrackets = map[byte]byte{'(': ')', '[': ']', '<': '>'}
+
+// FormatHeader fills a header format's {emoji}, {type}, {scope}, {breaking}
+// and {subject} placeholders; {breaking} becomes "!" for breaking changes.
+// Without a scope, the brackets around {scope}, or the separator before
+// it, are dropped as well, so "{type}({scope}): {subject}" gives
+// "fix: subject" and "{type}/{scope}: {subject}" gives "fix: subject".
+func FormatHeader(format, emoji, commitType, scope string, breaking bool, subject string) string {
+	if scope == "" {
+		format = dropScope(format)
+	}
+	bang := ""
+	if breaking {
+		bang = "!"
+	}
+	return strings.NewReplacer(
+		"{emoji}", emoji,
+		"{type}", commitType,
+		"{scope}", scope,
+		"{breaking}", bang,
+		"{subject}", subject,
+	).Replace(format)
+}
+
+// dropScope removes every {scope} from format together with its brackets
+// or, failing that, the punctuation character just before it. A space left
+// dangling by the removal goes too, so "[{scope}] {subject}" gives
+// "{subject}" rather than " {subject}".
+func dropScope(format string) string {
+	const placeholder = "{scope}"
+	for {
+		i := strings.Index(format, placeholder)
+		if i < 0 {
+			return format
+		}
+		start, end := i, i+len(placeholder)
+		switch {
+		case start > 0 && end < len(format) && scopeBrackets[format[start-1]] == format[end]:
+			start--
+			end++
+		case start > 0 && strings.IndexByte("/-_.:|,", format[start-1]) >= 0:
+			start--
+		}
+		format = format[:start] + format[end:]
+		switch {
+		case start == 0:
+			format = strings.TrimLeft(format, " ")
+		case format[start-1] == ' ' && (start == len(format) || strings.IndexByte(" :,.)]>", format[start]) >= 0):
+			format = format[:start-1] + format[start:]
+		}
+	}
+}
+
+// Paragraphs returns the message split into the paragraphs passed to git,
+// each of which becomes a separate -m argument.
+func (m Message) Paragraphs() []string {
+	paragraphs := []string{m.Header()}
+	if m.Body != "" {
+		paragraphs = append(paragraphs, m.Body)
+	}
+	if footers := m.Footers(); len(footers) > 0 {
+		// Trailers must share the final paragraph for git to parse them.
+		paragraphs = append(paragraphs, strings.Join(footers, "\n"))
+	}
+	return paragraphs
+}
+
+// String returns the full message exactly as git will record it.
+func (m Message) String() string {
+	return strings.Join(m.Paragraphs(), "\n\n")
+}
+
+// Footers returns the trailer lines placed at the end of the message.
+func (m Message) Footers() []string {
+	var footers []string
+	switch {
+	case m.Breaking && m.BreakingDesc != "":
+		footers = append(footers, "BREAKING CHANGE: "+m.BreakingDesc)
+	case m.Breaking && (m.Gitmoji != "" || m.HeaderFormat != "" && !strings.Contains(m.HeaderFormat, "{breaking}")):
+		// Gitmoji headers and formats without {breaking} can't carry the
+		// "!", so the footer marks the change.
+		footers = append(footers, "BREAKING CHANGE: "+m.Subject)
+	}
+	for _, coauthor := range m.Coauthors {
+		footers = append(footers, "Co-authored-by: "+coauthor)
+	}
+	footers = append(footers, m.Refs...)
+	footers = append(footers, m.Trailers...)
+	return footers
+}
+
+// IdentityPattern loosely matches a "Name <email>" identity, as used for
+// authors and co-authors.
+var IdentityPattern = regexp.MustCompile(`^[^<>]+ <[^<>\s@]+@[^<>\s@]+\.[^<>\s@]+>$`)
+
+// RefPattern loosely matches an issue reference footer such as
+// "Closes #123" or "Refs: JIRA-456".
+var RefPattern = regexp.MustCompile(`^[A-Za-z][\w-]*(?::\s*|\s+)(?:#\d+|[A-Z][A-Z0-9]+-\d+)$`)
+
+// trailerKeyPattern matches a trailer key git accepts, such as
+// "Reviewed-by".
+var trailerKeyPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9-]*$`)
+
+// ParseTrailer splits a "Key: value" trailer line into its key and value.
+// The error explains why git wouldn't read the line as a trailer.
+func ParseTrailer(line string) (key, value string, err error) {
+	key, value, ok := strings.Cut(line, ":")
+	key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+	switch {
+	case !ok:
+		return "", "", fmt.Errorf("%q is missing the colon, as in \"Reviewed-by: Name <email>\"", line)
+	case !trailerKeyPattern.MatchString(key):
+		return "", "", fmt.Errorf("%q isn't a valid trailer key; use only letters, digits and dashes", key)
+	case value == "":
+		return "", "", fmt.Errorf("the %s trailer needs a value", key)
+	}
+	return key, value, nil
+}
+
+// headerPattern matches a conventional commit header such as
+// "feat(api)!: add endpoint". The type may carry an emoji prefix, which is
+// captured separately.
+var headerPattern = regexp.MustCompile(`^([^\w\s(:!]*)([A-Za-z][\w-]*)(?:\(([^)]*)\))?(!)?:\s+(\S.*)$`)
+
+// gitmojiHeaderPattern matches the header of a gitmoji commit, such as
+// ":sparkles: (api): add endpoint" or "✨ add endpoint".
+var gitmojiHeaderPattern = regexp.MustCompile(`^(:[a-z0-9_+-]+:|\p{So}[\x{FE0F}\x{200D}\p{So}]*)\s*(?:\(([^)]*)\):)?\s*(\S.*)$`)
+
+// trailerPattern matches the first line of a git trailer: a token followed
+// by ": " or " #", as in "Signed-off-by: Jane <jane@example.com>" or
+// "Closes #123".
+var trailerPattern = regexp.MustCompile(`^(BREAKING[ -]CHANGE|[A-Za-z][\w-]*)(?::\s|\s#)`)
+
+// ParseConventional splits a raw commit message into its parts. It reports
+// false when the header doesn't follow the conventional or gitmoji format,
+// in which case the whole first line is returned as the subject. Either way
+// the body and footers are parsed, so nothing of the message is lost.
+//
+// Footers are only read from the last paragraph, and only when every line
+// of it is a trailer or the indented continuation of one, the same way git
+// finds trailers. Breaking changes, co-authors and issue references fill
+// their own fields; any other trailer, such as Signed-off-by, is kept in
+// Trailers.
+func ParseConventional(raw string) (Message, bool) {
+	raw = strings.ReplaceAll(raw, "\r\n", "\n")
+	lines := strings.Split(strings.TrimSpace(raw), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRightFunc(line, unicode.IsSpace)
+	}
+	var msg Message
+
+	paragraphs := splitParagraphs(lines[1:])
+	if n := len(paragraphs); n > 0 && isTrailerBlock(paragraphs[n-1]) {
+		msg.parseFooters(paragraphs[n-1])
+		paragraphs = paragraphs[:n-1]
+	}
+	bodies := make([]string, len(paragraphs))
+	for i, p := range paragraphs {
+		bodies[i] = strings.Join(p, "\n")
+	}
+	msg.Body = strings.Join(bodies, "\n\n")
+
+	header := strings.TrimSpace(lines[0])
+	if match := headerPattern.FindStringSubmatch(header); match != nil {
+		msg.Emoji = match[1]
+		msg.Type = match[2]
+		msg.Scope = strings.TrimSpace(match[3])
+		msg.Breaking = msg.Breaking || match[4] == "!"
+		msg.Subject = strings.TrimSpace(match[5])
+		return msg, true
+	}
+	if match := gitmojiHeaderPattern.FindStringSubmatch(header); match != nil {
+		msg.Gitmoji = match[1]
+		msg.Scope = strings.TrimSpace(match[2])
+		msg.Subject = strings.TrimSpace(match[3])
+		return msg, true
+	}
+	msg.Subject = header
+	return msg, false
+}
+
+// splitParagraphs groups lines into the paragraphs between blank lines.
+func splitParagraphs(lines []string) [][]string {
+	var paragraphs [][]string
+	var current []string
+	for _, line := range lines {
+		if line == "" {
+			if current != nil {
+				paragraphs = append(paragraphs, current)
+				current = nil
+			}
+			continue
+		}
+		current = append(current, line)
+	}
+	if current != nil {
+		paragraphs = append(paragraphs, current)
+	}
+	return paragraphs
+}
+
+// isTrailerBlock reports whether a paragraph holds only trailers, each
+// possibly continued on indented lines.
+func isTrailerBlock(paragraph []string) bool {
+	if !trailerPattern.MatchString(paragraph[0]) {
+		return false
+	}
+	for _, line := range paragraph[1:] {
+		if !trailerPattern.MatchString(line) && !isContinuation(line) {
+			return false
+		}
+	}
+	return true
+}
+
+// isContinuation reports whether a trailer line continues the one above.
+func isContinuation(line string) bool {
+	return strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")
+}
+
+// parseFooters sorts the trailers of a trailer block into the message
+// fields. Continuation lines of a breaking change are joined into its
+// description; those of other trailers are kept as they are.
+func (m *Message) parseFooters(paragraph []string) {
+	var footers []string
+	for _, line := range paragraph {
+		if isContinuation(line) && len(footers) > 0 {
+			footers[len(footers)-1] += "\n" + line
+			continue
+		}
+		footers = append(footers, line)
+	}
+	for _, footer := range footers {
+		token := trailerPattern.FindStringSubmatch(footer)[1]
+		switch {
+		case token == "BREAKING CHANGE" || token == "BREAKING-CHANGE":
+			m.Breaking = true
+			m.BreakingDesc = strings.Join(strings.Fields(strings.TrimLeft(footer[len(token):], ":#")), " ")
+		case strings.EqualFold(token, "Co-authored-by"):
+			m.Coauthors = append(m.Coauthors, strings.TrimSpace(footer[len(token)+1:]))
+		case RefPattern.MatchString(footer):
+			m.Refs = append(m.Refs, footer)
+		default:
+			m.Trailers = append(m.Trailers, footer)
+		}
+	}
+}
+
+// listItemPattern matches the marker starting a list item, such as "- ",
+// "* " or "1. ".
+var listItemPattern = regexp.MustCompile(`^(?:[-*+]|\d+[.)])\s+`)
+
+// WrapBody reflows body into lines of at most width columns. Blank lines
+// between paragraphs are kept, list items keep their marker with the lines
+// after it indented under the text, and other indented lines, such as code,
+// are left as they are. A word longer than width gets a line of its own. A
+// width of zero or less returns body unchanged.
+func WrapBody(body string, width int) string {
+	if width <= 0 || body == "" {
+		return body
+	}
+	var lines, words []string
+	var marker string
+	flush := func() {
+		if len(words) > 0 {
+			lines = append(lines, wrapWords(marker, words, width)...)
+		}
+		words, marker = nil, ""
+	}
+	for _, line := range strings.Split(body, "\n") {
+		indented := strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")
+		switch {
+		case strings.TrimSpace(line) == "":
+			flush()
+			lines = append(lines, "")
+		case indented && marker != "":
+			// The continuation of a list item.
+			words = append(words, strings.Fields(line)...)
+		case indented:
+			flush()
+			lines = append(lines, line)
+		case listItemPattern.MatchString(line):
+			flush()
+			marker = listItemPattern.FindString(line)
+			words = strings.Fields(line[len(marker):])
+		default:
+			words = append(words, strings.Fields(line)...)
+		}
+	}
+	flush()
+	return strings.Join(lines, "\n")
+}
+
+// wrapWords fills lines of at most width columns with words, starting the
+// first with marker and indenting the others to line up after it.
+func wrapWords(marker string, words []string, width int) []string {
+	indent := strings.Repeat(" ", utf8.RuneCountInString(marker))
+	var lines []string
+	line := marker + words[0]
+	for _, word := range words[1:] {
+		if utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) > width {
+			lines = append(lines, line)
+			line = indent + word
+			continue
+		}
+		line += " " + word
+	}
+	return append(lines, line)
+}
@@ -0,0 +1,217 @@
+package git
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseConventional(t *testing.T) {
+	tests := []struct {
+		name   string
+		raw    string
+		want   Message
+		wantOK bool
+	}{
+		{
+			name:   "header only",
+			raw:    "feat: add login",
+			want:   Message{Type: "feat", Subject: "add login"},
+			wantOK: true,
+		},
+		{
+			name:   "scope",
+			raw:    "fix( api ): handle timeouts\n",
+			want:   Message{Type: "fix", Scope: "api", Subject: "handle timeouts"},
+			wantOK: true,
+		},
+		{
+			name:   "breaking marker",
+			raw:    "feat(api)!: drop v1",
+			want:   Message{Type: "feat", Scope: "api", Subject: "drop v1", Breaking: true},
+			wantOK: true,
+		},
+		{
+			name:   "emoji prefix",
+			raw:    "🐛fix: stop crash",
+			want:   Message{Emoji: "🐛", Type: "fix", Subject: "stop crash"},
+			wantOK: true,
+		},
+		{
+			name: "multi-line body",
+			raw:  "docs: rewrite guide\n\nThe old guide was out of date.\nIt now covers setup.\n\n- install\n- configure",
+			want: Message{
+				Type:    "docs",
+				Subject: "rewrite guide",
+				Body:    "The old guide was out of date.\nIt now covers setup.\n\n- install\n- configure",
+			},
+			wantOK: true,
+		},
+		{
+			name: "footers",
+			raw: "feat: add login\n\nUses OAuth.\n\n" +
+				"Co-authored-by: Jane Doe <jane@example.com>\nCloses #12\nRefs: JIRA-456\nSigned-off-by: Sam Roe <sam@example.com>",
+			want: Message{
+				Type:      "feat",
+				Subject:   "add login",
+				Body:      "Uses OAuth.",
+				Coauthors: []string{"Jane Doe <jane@example.com>"},
+				Refs:      []string{"Closes #12", "Refs: JIRA-456"},
+				Trailers:  []string{"Signed-off-by: Sam Roe <sam@example.com>"},
+			},
+			wantOK: true,
+		},
+		{
+			name: "breaking change footer with continuation",
+			raw:  "feat: drop v1\n\nBREAKING CHANGE: the v1 endpoints\n  are gone\nCloses #7",
+			want: Message{
+				Type:         "feat",
+				Subject:      "drop v1",
+				Breaking:     true,
+				BreakingDesc: "the v1 endpoints are gone",
+				Refs:         []string{"Closes #7"},
+			},
+			wantOK: true,
+		},
+		{
+			name: "hyphenated breaking change footer",
+			raw:  "refactor!: rename config keys\n\nBREAKING-CHANGE: noEmoji\n\tis now emoji",
+			want: Message{
+				Type:         "refactor",
+				Subject:      "rename config keys",
+				Breaking:     true,
+				BreakingDesc: "noEmoji is now emoji",
+			},
+			wantOK: true,
+		},
+		{
+			name: "continuation of another trailer",
+			raw:  "fix: retry\n\nNote: first line\n  second line",
+			want: Message{
+				Type:     "fix",
+				Subject:  "retry",
+				Trailers: []string{"Note: first line\n  second line"},
+			},
+			wantOK: true,
+		},
+		{
+			name: "CRLF",
+			raw:  "fix(ui): stop flicker\r\n\r\nRedraw less often.\r\n\r\nCloses #9\r\n",
+			want: Message{
+				Type:    "fix",
+				Scope:   "ui",
+				Subject: "stop flicker",
+				Body:    "Redraw less often.",
+				Refs:    []string{"Closes #9"},
+			},
+			wantOK: true,
+		},
+		{
+			name: "footers only in the last paragraph",
+			raw:  "fix: retry\n\nCloses #1\n\nMore details.",
+			want: Message{
+				Type:    "fix",
+				Subject: "retry",
+				Body:    "Closes #1\n\nMore details.",
+			},
+			wantOK: true,
+		},
+		{
+			name: "last paragraph mixing trailers and text",
+			raw:  "fix: retry\n\nCloses #1\nand some prose",
+			want: Message{
+				Type:    "fix",
+				Subject: "retry",
+				Body:    "Closes #1\nand some prose",
+			},
+			wantOK: true,
+		},
+		{
+			name:   "gitmoji shortcode",
+			raw:    ":sparkles: (api): add endpoint",
+			want:   Message{Gitmoji: ":sparkles:", Scope: "api", Subject: "add endpoint"},
+			wantOK: true,
+		},
+		{
+			name:   "gitmoji emoji",
+			raw:    "✨ add endpoint\n\nCloses #4",
+			want:   Message{Gitmoji: "✨", Subject: "add endpoint", Refs: []string{"Closes #4"}},
+			wantOK: true,
+		},
+		{
+			name: "gitmoji breaking change",
+			raw:  ":boom: drop v1 api\n\nBREAKING CHANGE: drop v1 api",
+			want: Message{
+				Gitmoji:      ":boom:",
+				Subject:      "drop v1 api",
+				Breaking:     true,
+				BreakingDesc: "drop v1 api",
+			},
+			wantOK: true,
+		},
+		{
+			name:   "unknown type is left to the caller",
+			raw:    "wip: try things",
+			want:   Message{Type: "wip", Subject: "try things"},
+			wantOK: true,
+		},
+		{
+			name: "free-form",
+			raw:  "Merge branch 'main' into feature\n\nConflicts resolved.\n\nSigned-off-by: Sam Roe <sam@example.com>",
+			want: Message{
+				Subject:  "Merge branch 'main' into feature",
+				Body:     "Conflicts resolved.",
+				Trailers: []string{"Signed-off-by: Sam Roe <sam@example.com>"},
+			},
+		},
+		{
+			name: "missing space after the colon",
+			raw:  "feat:add login",
+			want: Message{Subject: "feat:add login"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ParseConventional(tt.raw)
+			if ok != tt.wantOK {
+				t.Errorf("ParseConventional() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseConventional() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGitmojiBreakingFooter(t *testing.T) {
+	msg := Message{Type: "feat", Gitmoji: ":sparkles:", Subject: "drop v1 api", Breaking: true}
+	want := ":sparkles: drop v1 api\n\nBREAKING CHANGE: drop v1 api"
+	if got := msg.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	msg.BreakingDesc = "the v1 endpoints are gone"
+	want = ":sparkles: drop v1 api\n\nBREAKING CHANGE: the v1 endpoints are gone"
+	if got := msg.String(); got != want {
+		t.Errorf("String() with a description = %q, want %q", got, want)
+	}
+}
+
+func TestParseConventionalRoundTrip(t *testing.T) {
+	msg := Message{
+		Type:         "feat",
+		Scope:        "api",
+		Subject:      "add endpoint",
+		Body:         "Adds /v2/users.\n\nPaginated.",
+		Breaking:     true,
+		BreakingDesc: "v1 is gone",
+		Coauthors:    []string{"Jane Doe <jane@example.com>"},
+		Refs:         []string{"Closes #12"},
+		Trailers:     []string{"Reviewed-by: Sam Roe <sam@example.com>"},
+	}
+	got, ok := ParseConventional(msg.String())
+	if !ok {
+		t.Fatal("ParseConventional() didn't parse its own message")
+	}
+	if !reflect.DeepEqual(got, msg) {
+		t.Errorf("ParseConventional(%q) = %+v, want %+v", msg.String(), got, msg)
+	}
+}
@@ -0,0 +1,119 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Signing formats, as set in gpg.format.
+const (
+	SigningOpenPGP = "openpgp"
+	SigningX509    = "x509"
+	SigningSSH     = "ssh"
+)
+
+// defaultSigningPrograms are the programs git signs with for each format
+// when gpg.<format>.program isn't set.
+var defaultSigningPrograms = map[string]string{
+	SigningOpenPGP: "gpg",
+	SigningX509:    "gpgsm",
+	SigningSSH:     "ssh-keygen",
+}
+
+// SigningMethod describes how git will sign a commit.
+type SigningMethod struct {
+	// Format is the gpg.format in effect.
+	Format string
+	// Key is the key passed with -S or, failing that, user.signingKey. It
+	// is empty when git picks one from the committer identity.
+	Key string
+	// KeyFromConfig reports whether Key came from user.signingKey.
+	KeyFromConfig bool
+}
+
+// Name returns the format as shown to the user, such as "SSH".
+func (s SigningMethod) Name() string {
+	switch s.Format {
+	case SigningSSH:
+		return "SSH"
+	case SigningX509:
+		return "X.509"
+	}
+	return "OpenPGP"
+}
+
+// Signing returns how git will sign a commit made with -S<key>, following
+// the repository's gpg.format. It fails when git would: for an unknown
+// format, a missing signing program, or an SSH setup without a key.
+func (r *Repo) Signing(key string) (SigningMethod, error) {
+	method := SigningMethod{Format: SigningOpenPGP, Key: key}
+	if format, err := r.output("config", "gpg.format"); err == nil && format != "" {
+		method.Format = strings.ToLower(format)
+	}
+	program, ok := defaultSigningPrograms[method.Format]
+	if !ok {
+		return method, fmt.Errorf("unsupported gpg.format %q (expected openpgp, x509 or ssh)", method.Format)
+	}
+
+	// gpg.program is the legacy name of gpg.openpgp.program.
+	programKeys := []string{"gpg." + method.Format + ".program"}
+	if method.Format == SigningOpenPGP {
+		programKeys = append(programKeys, "gpg.program")
+	}
+	for _, k := range programKeys {
+		if p, err := r.output("config", k); err == nil && p != "" {
+			program = p
+			break
+		}
+	}
+	if _, err := exec.LookPath(program); err != nil {
+		return method, fmt.Errorf("%s signing needs %s, which isn't installed", method.Name(), program)
+	}
+
+	if method.Key == "" {
+		method.Key, _ = r.output("config", "user.signingKey")
+		method.KeyFromConfig = method.Key != ""
+	}
+	if method.Format != SigningSSH {
+		// gpg and gpgsm fall back to the key of the committer identity.
+		return method, nil
+	}
+	if method.Key == "" {
+		if cmd, err := r.output("config", "gpg.ssh.defaultKeyCommand"); err == nil && cmd != "" {
+			return method, nil
+		}
+		return method, fmt.Errorf("SSH signing needs a key: set user.signingKey or pass --signing-key")
+	}
+	root, _ := r.Root()
+	if err := checkSSHKey(method.Key, root); err != nil {
+		return method, err
+	}
+	return method, nil
+}
+
+// checkSSHKey makes sure an SSH signing key given as a file exists. Like
+// git, it resolves relative paths against root, the top of the work tree.
+// Keys given literally, as "key::..." or "ssh-...", are left to ssh-keygen.
+func checkSSHKey(key, root string) error {
+	if strings.HasPrefix(key, "key::") || strings.HasPrefix(key, "ssh-") || strings.HasPrefix(key, "ecdsa-") {
+		return nil
+	}
+	path := key
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return err
+		}
+		path = filepath.Join(home, rest)
+	}
+	if !filepath.IsAbs(path) && root != "" {
+		path = filepath.Join(root, path)
+	}
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("SSH signing key %s not found", key)
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+// Package gocommit assembles conventional commit messages and commits them,
+// for Go programs that want gocommit's commit logic without its TUI. The
+// message follows the same configuration as the gocommit binary.
+package gocommit
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"StevenD2002/GoCommit/config"
+	"StevenD2002/GoCommit/git"
+)
+
+// Options describe the commit to make.
+type Options struct {
+	// Dir is the repository to commit in; empty means the current
+	// directory.
+	Dir string
+	// Runner runs git. When nil, the git binary on the PATH runs in Dir.
+	Runner git.GitRunner
+	// Config supplies the commit types and message rules. When nil, the
+	// repository's configuration is loaded, as the gocommit binary does.
+	Config *config.Config
+
+	// Type is the title of one of the configured commit types.
+	Type    string
+	Scope   string
+	Subject string
+	Body    string
+	// Breaking marks a breaking change, described in BreakingDesc when
+	// set.
+	Breaking     bool
+	BreakingDesc string
+	// Coauthors are added as Co-authored-by trailers, as "Name <email>".
+	Coauthors []string
+	// Refs are issue references such as "Closes #12", one per line.
+	Refs []string
+	// Trailers are other "Key: value" footers, such as "Reviewed-by: ...".
+	Trailers []string
+
+	// Flags are the git commit flags. DryRun is ignored; call Message to
+	// assemble the message without committing.
+	Flags git.CommitOptions
+	// StageAll stages changes to tracked files first (git commit -a).
+	StageAll bool
+}
+
+// ErrNothingStaged is returned by Commit when there is nothing to commit
+// and neither Amend nor AllowEmpty is set.
+var ErrNothingStaged = errors.New("no files staged for commit")
+
+// CommitError is returned when git commit fails. Output holds what git and
+// the hooks printed.
+type CommitError struct {
+	Output string
+	Err    error
+}
+
+func (e *CommitError) Error() string {
+	return "git commit: " + e.Err.Error()
+}
+
+func (e *CommitError) Unwrap() error {
+	return e.Err
+}
+
+// repo returns the repository the options point at.
+func (o Options) repo() *git.Repo {
+	if o.Runner != nil {
+		return git.NewRepo(o.Runner)
+	}
+	return git.NewRepo(git.ExecRunner{Dir: o.Dir})
+}
+
+// config returns o.Config, or the configuration of repo when it is nil.
+// Warnings about the config file are dropped.
+func (o Options) config(repo *git.Repo) (config.Config, error) {
+	if o.Config != nil {
+		return *o.Config, nil
+	}
+	root, _ := repo.Root()
+	cfg, _, err := config.Load(root)
+	if err != nil {
+		return cfg, fmt.Errorf("loading config: %w", err)
+	}
+	return cfg, nil
+}
+
+// Message assembles the commit message for opts without committing. It
+// applies the type's emoji and subject template, the subject rules, body
+// wrapping and the header format of the configuration, and fails when the
+// type is unknown, a co-author or trailer is malformed or the header doesn't
+// match headerPattern.
+func Message(opts Options) (git.Message, error) {
+	cfg, err := opts.config(opts.repo())
+	if err != nil {
+		return git.Message{}, err
+	}
+	return build(cfg, opts)
+}
+
+// build assembles the message for opts under cfg.
+func build(cfg config.Config, opts Options) (git.Message, error) {
+	msg := git.Message{
+		Type:         opts.Type,
+		HeaderFormat: cfg.HeaderFormat,
+		Scope:        strings.TrimSpace(opts.Scope),
+		Subject:      cfg.SubjectRules().Normalize(opts.Subject),
+		Body:         git.WrapBody(strings.TrimSpace(opts.Body), cfg.WrapBody),
+		Breaking:     opts.Breaking || opts.BreakingDesc != "",
+		BreakingDesc: strings.TrimSpace(opts.BreakingDesc),
+		Refs:         opts.Refs,
+	}
+	if msg.Subject == "" {
+		return msg, errors.New("the subject can't be empty")
+	}
+	for _, c := range opts.Coauthors {
+		if !git.IdentityPattern.MatchString(c) {
+			return msg, fmt.Errorf("co-author %q must look like \"Name <email>\"", c)
+		}
+		msg.Coauthors = append(msg.Coauthors, c)
+	}
+	for _, t := range opts.Trailers {
+		key, value, err := git.ParseTrailer(t)
+		if err != nil {
+			return msg, err
+		}
+		msg.Trailers = append(msg.Trailers, key+": "+value)
+	}
+
+	var titles []string
+	for _, t := range cfg.Types {
+		if t.Title == opts.Type {
+			msg.Subject = config.ApplySubject(t.Subject, msg.Subject)
+			msg.Gitmoji = cfg.GitmojiFor(t)
+			if !cfg.NoEmoji && msg.Gitmoji == "" {
+				msg.Emoji = cfg.EmojiFor(t)
+			}
+			return msg, checkHeaderPattern(cfg, msg)
+		}
+		titles = append(titles, t.Title)
+	}
+	return msg, fmt.Errorf("unknown commit type %q (expected one of: %s)", opts.Type, strings.Join(titles, ", "))
+}
+
+// checkHeaderPattern makes sure the header matches the configured
+// headerPattern, if any.
+func checkHeaderPattern(cfg config.Config, msg git.Message) error {
+	if cfg.HeaderPattern == "" {
+		return nil
+	}
+	pattern, err := regexp.Compile(cfg.HeaderPattern)
+	if err != nil {
+		return fmt.Errorf("invalid headerPattern: %w", err)
+	}
+	if header := msg.Header(); !pattern.MatchString(header) {
+		return fmt.Errorf("%q doesn't match the required pattern %s", header, pattern)
+	}
+	return nil
+}
+
+// Commit assembles the message for opts, commits it and returns the SHA of
+// the new commit. A failing git commit returns a *CommitError.
+func Commit(opts Options) (string, error) {
+	repo := opts.repo()
+	if err := repo.Check(); err != nil {
+		return "", err
+	}
+	cfg, err := opts.config(repo)
+	if err != nil {
+		return "", err
+	}
+	msg, err := build(cfg, opts)
+	if err != nil {
+		return "", err
+	}
+
+	flags := opts.Flags
+	flags.DryRun = false
+	if flags.Sign {
+		if _, err := repo.Signing(flags.SigningKey); err != nil {
+			return "", fmt.Errorf("can't sign the commit: %w", err)
+		}
+	}
+	if opts.StageAll {
+		if err := repo.StageTracked(); err != nil {
+			return "", fmt.Errorf("staging tracked files: %w", err)
+		}
+	}
+	if !flags.Amend && !flags.AllowEmpty {
+		staged, err := repo.StagedFiles()
+		if err != nil {
+			return "", err
+		}
+		if len(staged) == 0 {
+			return "", ErrNothingStaged
+		}
+	}
+
+	if output, err := repo.Commit(msg, flags); err != nil {
+		return "", &CommitError{Output: output, Err: err}
+	}
+	sha, err := repo.HeadSHA()
+	if err != nil {
+		return "", fmt.Errorf("reading HEAD: %w", err)
+	}
+	return sha, nil
+}
@@ -1,256 +1,298 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
 	"os"
-	"os/exec"
 	"strings"
+	"time"
 
-	"github.com/charmbracelet/bubbles/list"
-	"github.com/charmbracelet/bubbles/textinput"
+	"StevenD2002/GoCommit/config"
+	"StevenD2002/GoCommit/git"
+	"StevenD2002/GoCommit/ui"
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/charmbracelet/lipgloss"
 )
 
-var (
-	appStyle   = lipgloss.NewStyle().Padding(1, 2)
-	titleStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FFFDF5")).
-			Background(lipgloss.Color("#25A065")).
-			Padding(0, 1)
-	itemStyle = lipgloss.NewStyle().PaddingLeft(4)
-	pageStyle = lipgloss.NewStyle().PaddingLeft(2).Foreground(lipgloss.Color("#888888"))
-)
-
-type commitType struct {
-	title, desc string
-}
-
-func (c commitType) Title() string       { return c.title }
-func (c commitType) Description() string { return c.desc }
-func (c commitType) FilterValue() string { return c.title }
-
-type model struct {
-	stagedFiles    []string
-	allCommitTypes []list.Item
-	commitTypes    list.Model
-	textInput      textinput.Model
-	selectedType   string
-	state          int // 0: select type, 1: enter message, 2: confirm
-	err            error
-	currentPage    int
-	totalPages     int
-}
-
-func getGitStagedFiles() ([]string, error) {
-	cmd := exec.Command("git", "diff", "--name-only", "--cached")
-	output, err := cmd.Output()
-	if err != nil {
-		return nil, err
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "version" {
+		fmt.Println(versionString())
+		return
 	}
 
-	files := strings.Split(strings.TrimSpace(string(output)), "\n")
-	if len(files) == 1 && files[0] == "" {
-		return []string{}, nil
+	noEmoji := flag.Bool("no-emoji", false, "commit plain types without emoji prefixes")
+	sign := flag.Bool("sign", false, "sign the commit (git commit -S)")
+	signingKey := flag.String("signing-key", "", "key id to sign the commit with; implies --sign")
+	amend := flag.Bool("amend", false, "rewrite the HEAD commit, starting from its message")
+	dryRun := flag.Bool("dry-run", false, "print the assembled message instead of committing")
+	noVerify := flag.Bool("no-verify", false, "skip the pre-commit and commit-msg hooks")
+	allowEmpty := flag.Bool("allow-empty", false, "allow a commit without staged changes")
+	author := flag.String("author", "", `override the commit author ("Name <email>")`)
+	signoff := flag.Bool("signoff", false, "add a Signed-off-by trailer (git commit -s)")
+	date := flag.String("date", "", "set the author date, such as 2024-05-01 or 2024-05-01T15:04:05+02:00")
+	push := flag.Bool("push", false, "run git push after a successful commit")
+	openPR := flag.Bool("pr", false, "push and open a GitHub pull request with gh after committing")
+	editAfter := flag.Bool("edit-after", false, "open the new commit in git's editor (git commit --amend) to refine its message")
+	minimal := flag.Bool("minimal", false, "hide the staged files panel and hints, for small terminals")
+	allowDuplicate := flag.Bool("allow-duplicate", false, "don't ask before committing the same subject as the last commit")
+	var stageAll bool
+	flag.BoolVar(&stageAll, "all", false, "stage changes to tracked files before committing (git commit -a)")
+	flag.BoolVar(&stageAll, "a", false, "stage changes to tracked files before committing (shorthand)")
+	viaFile := flag.Bool("via-file", false, "pass the message to git commit -F in a temporary file instead of -m")
+	messageFile := flag.String("message-file", "", "pre-fill the TUI from a draft message in this file")
+	manageTypes := flag.Bool("manage-types", false, "edit the commit types and save them to the config file")
+	cwd := flag.String("cwd", "", "run git in this directory instead of the current one")
+
+	var cli cliMessage
+	flag.StringVar(&cli.commitType, "type", "", "commit type; with --message, commits without the TUI")
+	flag.StringVar(&cli.scope, "scope", "", "commit scope (non-interactive mode)")
+	flag.StringVar(&cli.subject, "message", "", "commit subject; with --type, commits without the TUI")
+	flag.StringVar(&cli.body, "body", "", "commit body (non-interactive mode)")
+	flag.BoolVar(&cli.breaking, "breaking", false, "mark the commit as breaking (non-interactive mode)")
+	jsonOutput := flag.Bool("json", false, "print the result as JSON (non-interactive mode)")
+	var showVersion bool
+	flag.BoolVar(&showVersion, "version", false, "print the version and exit")
+	flag.BoolVar(&showVersion, "v", false, "print the version and exit (shorthand)")
+	var revertRev string
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "completion":
+			os.Exit(runCompletion(os.Args[2:]))
+		case typesCommand:
+			printTypes(os.Args[2:])
+			return
+		case "types":
+			os.Exit(runTypes(os.Args[2:]))
+		case "schema":
+			os.Exit(printSchema())
+		case "revert":
+			if len(os.Args) < 3 || strings.HasPrefix(os.Args[2], "-") {
+				fmt.Fprintln(os.Stderr, "Usage: gocommit revert <commit> [flags]")
+				os.Exit(2)
+			}
+			// The flags may follow the commit.
+			revertRev = os.Args[2]
+			os.Args = append(os.Args[:1], os.Args[3:]...)
+		}
 	}
-	return files, nil
-}
+	flag.Parse()
 
-func createCommit(commitType string, message string) error {
-	fullMessage := fmt.Sprintf("%s: %s", commitType, message)
-	cmd := exec.Command("git", "commit", "-m", fullMessage)
-	return cmd.Run()
-}
+	if showVersion {
+		fmt.Println(versionString())
+		return
+	}
 
-func initialModel() (model, error) {
-	stagedFiles, err := getGitStagedFiles()
+	repo := git.NewRepo(git.ExecRunner{Dir: *cwd})
+	if *cwd != "" {
+		if info, err := os.Stat(*cwd); err != nil || !info.IsDir() {
+			fmt.Fprintf(os.Stderr, "Error: --cwd %s is not a directory\n", *cwd)
+			os.Exit(2)
+		}
+		if err := repo.Check(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: --cwd %s: %v\n", *cwd, err)
+			os.Exit(1)
+		}
+	}
+	root, _ := repo.Root()
+	cfg, warnings, err := config.Load(root)
 	if err != nil {
-		return model{}, err
+		fmt.Printf("Error loading config: %v\n", err)
+		os.Exit(1)
 	}
-
-	allCommitTypes := []list.Item{
-		commitType{title: "📦feat", desc: "A new feature"},
-		commitType{title: "🔨fix", desc: "A bug fix"},
-		commitType{title: "📝docs", desc: "Documentation only changes"},
-		commitType{title: "🎨style", desc: "Changes that do not affect the meaning of the code"},
-		commitType{title: "🧹refactor", desc: "A code change that neither fixes a bug nor adds a feature"},
-		commitType{title: "🚀perf", desc: "A code change that improves performance"},
-		commitType{title: "🧪test", desc: "Adding missing tests or correcting existing tests"},
-		commitType{title: "👷chore", desc: "Changes to the build process or auxiliary tools"},
+	if *noEmoji {
+		cfg.NoEmoji = true
 	}
-
-	// Set up delegate for the list
-	delegate := list.NewDefaultDelegate()
-	delegate.ShowDescription = true
-	delegate.Styles.SelectedTitle = delegate.Styles.SelectedTitle.Foreground(lipgloss.Color("170")).BorderForeground(lipgloss.Color("170"))
-	delegate.Styles.SelectedDesc = delegate.Styles.SelectedDesc.Foreground(lipgloss.Color("240"))
-
-	// Calculate total pages (4 items per page)
-	totalPages := (len(allCommitTypes) + 3) / 4
-
-	// Initial page is the first page
-	currentPage := 0
-
-	// Get the items for the first page
-	pageItems := getPageItems(allCommitTypes, currentPage, 4)
-
-	// Configure list with proper dimensions
-	l := list.New(pageItems, delegate, 60, 20)
-	l.SetShowStatusBar(false)
-	l.SetFilteringEnabled(false)
-	l.Styles.Title = titleStyle
-	l.Title = "Select commit type"
-
-	ti := textinput.New()
-	ti.Placeholder = "Enter commit message"
-	ti.Focus()
-	ti.CharLimit = 80
-	ti.Width = 60
-
-	return model{
-		stagedFiles:    stagedFiles,
-		allCommitTypes: allCommitTypes,
-		commitTypes:    l,
-		textInput:      ti,
-		state:          0,
-		currentPage:    currentPage,
-		totalPages:     totalPages,
-	}, nil
-}
-
-// Helper function to get items for a specific page
-func getPageItems(allItems []list.Item, page, itemsPerPage int) []list.Item {
-	startIdx := page * itemsPerPage
-	endIdx := startIdx + itemsPerPage
-	if endIdx > len(allItems) {
-		endIdx = len(allItems)
+	if *sign {
+		cfg.Sign = true
 	}
-
-	if startIdx >= len(allItems) {
-		return []list.Item{}
+	if *signingKey != "" {
+		cfg.Sign = true
+		cfg.SigningKey = *signingKey
+	}
+	if *push {
+		cfg.Push = true
+	}
+	if *openPR {
+		cfg.OpenPR = true
+	}
+	if *editAfter {
+		cfg.EditAfter = true
+	}
+	if *minimal {
+		cfg.Minimal = true
+	}
+	if *allowDuplicate {
+		cfg.AllowDuplicate = true
+	}
+	if *viaFile {
+		cfg.ViaFile = true
+	}
+	if stageAll {
+		cfg.StageAll = true
+	}
+	if *author != "" && !git.IdentityPattern.MatchString(*author) {
+		fmt.Fprintf(os.Stderr, "Error: --author must look like \"Name <email>\", got %q\n", *author)
+		os.Exit(2)
+	}
+	var commitDate string
+	if *date != "" {
+		t, err := git.ParseDate(*date)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: --date: %v\n", err)
+			os.Exit(2)
+		}
+		commitDate = t.Format(time.RFC3339)
+	}
+	for _, w := range warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
 	}
 
-	return allItems[startIdx:endIdx]
-}
-
-func (m model) Init() tea.Cmd {
-	return nil
-}
-
-func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	switch msg := msg.(type) {
-	case tea.KeyMsg:
-		switch msg.String() {
-		case "ctrl+c", "q":
-			return m, tea.Quit
+	if *manageTypes {
+		os.Exit(runTypeEditor(cfg, config.FilePath(root)))
+	}
 
-		case "tab":
-			if m.state == 0 {
-				// Switch to next page when tab is pressed
-				nextPage := (m.currentPage + 1) % m.totalPages
-				m.currentPage = nextPage
-				pageItems := getPageItems(m.allCommitTypes, m.currentPage, 4)
-				m.commitTypes.SetItems(pageItems)
-			}
+	opts := git.CommitOptions{
+		Sign:       cfg.Sign,
+		SigningKey: cfg.SigningKey,
+		Amend:      *amend,
+		NoVerify:   *noVerify,
+		AllowEmpty: *allowEmpty,
+		Author:     *author,
+		Signoff:    *signoff,
+		Date:       commitDate,
+		DryRun:     *dryRun,
+		ViaFile:    cfg.ViaFile,
+	}
 
-		case "enter":
-			switch m.state {
-			case 0: // select type
-				if i, ok := m.commitTypes.SelectedItem().(commitType); ok {
-					m.selectedType = i.title
-					m.state = 1
-				}
-			case 1: // enter message
-				if m.textInput.Value() != "" {
-					m.state = 2
-				}
-			case 2: // confirm
-				err := createCommit(m.selectedType, m.textInput.Value())
-				if err != nil {
-					m.err = err
-					return m, tea.Quit
-				}
-				return m, tea.Quit
+	if revertRev != "" {
+		if cli.provided() || *jsonOutput {
+			fmt.Fprintln(os.Stderr, "Error: revert commits through the TUI; it can't be combined with --type, --message or --json")
+			os.Exit(2)
+		}
+		sha, err := repo.ResolveCommit(revertRev)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if *dryRun {
+			msg, err := repo.RevertMessage(sha)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
 			}
+			fmt.Println(msg.String())
+			return
+		}
+		// The TUI picks up the revert in progress and starts from its
+		// message.
+		if err := repo.Revert(sha); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			fmt.Fprintln(os.Stderr, "Resolve any conflicts, stage the result and run gocommit to commit the revert.")
+			os.Exit(1)
 		}
 	}
 
-	switch m.state {
-	case 0:
-		var cmd tea.Cmd
-		m.commitTypes, cmd = m.commitTypes.Update(msg)
-		return m, cmd
-	case 1:
-		var cmd tea.Cmd
-		m.textInput, cmd = m.textInput.Update(msg)
-		return m, cmd
+	r := reporter{json: *jsonOutput}
+	if *editAfter && r.json {
+		os.Exit(r.fail(2, "", errors.New("--edit-after opens an editor and can't be combined with --json")))
 	}
-
-	return m, nil
-}
-
-func (m model) View() string {
-	if len(m.stagedFiles) == 0 {
-		return "No files staged for commit. Use 'git add' to stage files.\n"
+	// Catch a signing setup git would reject before the message is written.
+	if opts.Sign && !*dryRun {
+		if _, err := repo.Signing(opts.SigningKey); err != nil {
+			os.Exit(r.fail(1, "", fmt.Errorf("can't sign the commit: %w", err)))
+		}
 	}
-
-	if m.err != nil {
-		return fmt.Sprintf("Error: %v\n", m.err)
+	// Staging first lets the TUI list what will actually be committed. A dry
+	// run leaves the index as it is, and outside a repository the TUI
+	// reports the error.
+	if cfg.StageAll && !*dryRun && root != "" {
+		if err := repo.StageTracked(); err != nil {
+			os.Exit(r.fail(1, "", fmt.Errorf("staging tracked files: %w", err)))
+		}
 	}
-
-	var s string
-
-	// Show staged files
-	s += titleStyle.Render("Staged Files") + "\n"
-	for _, file := range m.stagedFiles {
-		s += itemStyle.Render(file) + "\n"
+	if cli.complete() {
+		os.Exit(runNonInteractive(repo, cfg, opts, cli, r))
 	}
-	s += "\n"
-
-	switch m.state {
-	case 0:
-		// Select commit type
-		s += m.commitTypes.View()
-		s += "\n"
-
-		// Show page navigation info
-		s += pageStyle.Render(fmt.Sprintf("Page %d/%d (Press Tab to switch pages)", m.currentPage+1, m.totalPages))
-
-	case 1:
-		// Enter commit message
-		s += titleStyle.Render("Commit Message") + "\n"
-		s += fmt.Sprintf("Type: %s\n\n", m.selectedType)
-		s += m.textInput.View()
-	case 2:
-		// Confirm
-		s += titleStyle.Render("Confirm Commit") + "\n"
-		s += fmt.Sprintf("Type: %s\n", m.selectedType)
-		s += fmt.Sprintf("Message: %s\n\n", m.textInput.Value())
-		s += "Press Enter to commit or q to quit"
+	if r.json {
+		os.Exit(r.fail(2, "", errors.New("--json requires --type and --message")))
+	}
+	if cli.provided() {
+		fmt.Fprintln(os.Stderr, "Both --type and --message are required to commit without the TUI.")
+		flag.Usage()
+		os.Exit(2)
 	}
 
-	return appStyle.Render(s)
-}
-
-func main() {
-	m, err := initialModel()
+	m, err := ui.New(cfg, opts, repo)
+	if errors.Is(err, git.ErrNotGitRepo) {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
 	if err != nil {
 		fmt.Printf("Error initializing: %v\n", err)
 		os.Exit(1)
 	}
 
-	if len(m.stagedFiles) == 0 {
+	if *messageFile != "" {
+		draft, err := os.ReadFile(*messageFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		m.Seed(string(draft))
+	}
+
+	if m.NothingStaged() {
 		fmt.Println("No files staged for commit. Use 'git add' to stage files.")
 		os.Exit(0)
 	}
 
-	p := tea.NewProgram(m)
-	if _, err := p.Run(); err != nil {
+	p := tea.NewProgram(m, tea.WithMouseCellMotion())
+	final, err := p.Run()
+	if err != nil {
 		fmt.Printf("Error running program: %v\n", err)
 		os.Exit(1)
 	}
+	if fm, ok := final.(ui.Model); ok {
+		if fm.TimedOut() {
+			fmt.Fprintf(os.Stderr, "Quit after %ds without input; nothing was committed.\n", cfg.IdleTimeout)
+			os.Exit(1)
+		}
+		if fm.Err() != nil {
+			os.Exit(1)
+		}
+		// A successful commit is reported on the TUI's last screen.
+		if out := fm.DryRunOutput(); out != "" {
+			fmt.Println(out)
+		}
+	}
+}
 
-	if m.state == 2 {
-		fmt.Println("Commit successful!")
+// runTypeEditor runs the commit type editor and returns the exit code.
+func runTypeEditor(cfg config.Config, path string) int {
+	e, err := ui.NewTypeEditor(cfg, path)
+	if err != nil {
+		fmt.Printf("Error initializing: %v\n", err)
+		return 1
+	}
+	final, err := tea.NewProgram(e).Run()
+	if err != nil {
+		fmt.Printf("Error running program: %v\n", err)
+		return 1
+	}
+	if fe, ok := final.(ui.TypeEditor); ok && fe.Saved() {
+		fmt.Printf("Saved commit types to %s\n", path)
+	}
+	return 0
+}
+
+// printSchema writes the JSON schema of the config file to stdout and
+// returns the exit code.
+func printSchema() int {
+	schema, err := config.Schema()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
 	}
+	fmt.Println(string(schema))
+	return 0
 }
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"text/tabwriter"
+
+	"StevenD2002/GoCommit/config"
+	"StevenD2002/GoCommit/git"
+)
+
+// typeResult is the --json description of a commit type.
+type typeResult struct {
+	Title    string   `json:"title"`
+	Desc     string   `json:"desc"`
+	Emoji    string   `json:"emoji"`
+	Gitmoji  string   `json:"gitmoji,omitempty"`
+	Subject  string   `json:"subject,omitempty"`
+	Keywords []string `json:"keywords,omitempty"`
+}
+
+// runTypes prints the commit types in effect after merging the config
+// files and environment, as a table or, with --json, a JSON array. It works
+// outside a repository too, with only the global config. It returns the exit
+// code. --cwd picks the repository as it does for a commit.
+func runTypes(args []string) int {
+	fs := flag.NewFlagSet("types", flag.ContinueOnError)
+	jsonOutput := fs.Bool("json", false, "print the types as JSON")
+	cwd := fs.String("cwd", "", "read the config of the repository in this directory")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: gocommit types [--json] [--cwd <path>]")
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() > 0 {
+		fs.Usage()
+		return 2
+	}
+
+	if info, err := os.Stat(*cwd); *cwd != "" && (err != nil || !info.IsDir()) {
+		fmt.Fprintf(os.Stderr, "Error: --cwd %s is not a directory\n", *cwd)
+		return 2
+	}
+	root, _ := git.NewRepo(git.ExecRunner{Dir: *cwd}).Root()
+	cfg, warnings, err := config.Load(root)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		return 1
+	}
+	for _, w := range warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
+	}
+
+	types := make([]typeResult, len(cfg.Types))
+	for i, t := range cfg.Types {
+		types[i] = typeResult{Title: t.Title, Desc: t.Desc, Emoji: cfg.EmojiFor(t), Gitmoji: t.Gitmoji, Subject: t.Subject, Keywords: t.Keywords}
+	}
+	if *jsonOutput {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(types); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing types: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+	writeTypesTable(os.Stdout, types)
+	return 0
+}
+
+// writeTypesTable prints one type per row under a header. The emoji comes
+// last since tabwriter can't measure its width; the gitmoji column is only
+// shown when a type has one.
+func writeTypesTable(w io.Writer, types []typeResult) {
+	gitmoji := slices.ContainsFunc(types, func(t typeResult) bool { return t.Gitmoji != "" })
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	if gitmoji {
+		fmt.Fprintln(tw, "TYPE\tDESCRIPTION\tGITMOJI\tEMOJI")
+	} else {
+		fmt.Fprintln(tw, "TYPE\tDESCRIPTION\tEMOJI")
+	}
+	for _, t := range types {
+		if gitmoji {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", t.Title, t.Desc, t.Gitmoji, t.Emoji)
+		} else {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", t.Title, t.Desc, t.Emoji)
+		}
+	}
+	tw.Flush()
+}
@@ -0,0 +1,31 @@
+package ui
+
+import (
+	"time"
+
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// copiedTimeout is how long the "copied!" indicator stays visible.
+const copiedTimeout = 2 * time.Second
+
+// copyDoneMsg reports the result of copying the message to the clipboard.
+type copyDoneMsg struct{ err error }
+
+// clearCopiedMsg hides the "copied!" indicator again.
+type clearCopiedMsg struct{}
+
+// copyCmd copies text to the system clipboard.
+func copyCmd(text string) tea.Cmd {
+	return func() tea.Msg {
+		return copyDoneMsg{err: clipboard.WriteAll(text)}
+	}
+}
+
+// clearCopiedCmd hides the indicator after copiedTimeout.
+func clearCopiedCmd() tea.Cmd {
+	return tea.Tick(copiedTimeout, func(time.Time) tea.Msg {
+		return clearCopiedMsg{}
+	})
+}
@@ -0,0 +1,119 @@
+package ui
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// commitlintConfigs are the files commitlint reads its rules from, besides
+// a "commitlint" key in package.json.
+var commitlintConfigs = []string{
+	".commitlintrc",
+	".commitlintrc.json",
+	".commitlintrc.yaml",
+	".commitlintrc.yml",
+	".commitlintrc.js",
+	".commitlintrc.cjs",
+	".commitlintrc.mjs",
+	".commitlintrc.ts",
+	"commitlint.config.js",
+	"commitlint.config.cjs",
+	"commitlint.config.mjs",
+	"commitlint.config.ts",
+}
+
+// commitlintAvailable reports whether the repository at root configures
+// commitlint and has it installed, and npx is there to run it.
+func commitlintAvailable(root string) bool {
+	if root == "" {
+		return false
+	}
+	if _, err := exec.LookPath("npx"); err != nil {
+		return false
+	}
+	if _, err := os.Stat(filepath.Join(root, "node_modules", ".bin", "commitlint")); err != nil {
+		return false
+	}
+	for _, name := range commitlintConfigs {
+		if _, err := os.Stat(filepath.Join(root, name)); err == nil {
+			return true
+		}
+	}
+	data, err := os.ReadFile(filepath.Join(root, "package.json"))
+	return err == nil && strings.Contains(string(data), `"commitlint"`)
+}
+
+// commitlintDoneMsg reports commitlint's verdict on message. errors and
+// warnings are the problems it listed; err is set when it couldn't run.
+type commitlintDoneMsg struct {
+	message  string
+	errors   []string
+	warnings []string
+	err      error
+}
+
+// commitlintCmd pipes message into commitlint in the repository at root.
+func commitlintCmd(root, message string) tea.Cmd {
+	return func() tea.Msg {
+		cmd := exec.Command("npx", "--no-install", "commitlint")
+		cmd.Dir = root
+		cmd.Stdin = strings.NewReader(message + "\n")
+		output, err := cmd.CombinedOutput()
+
+		result := commitlintDoneMsg{message: message}
+		scanner := bufio.NewScanner(strings.NewReader(string(output)))
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if problem, ok := strings.CutPrefix(line, "✖"); ok && !isCommitlintSummary(problem) {
+				result.errors = append(result.errors, strings.TrimSpace(problem))
+			} else if problem, ok := strings.CutPrefix(line, "⚠"); ok && !isCommitlintSummary(problem) {
+				result.warnings = append(result.warnings, strings.TrimSpace(problem))
+			}
+		}
+
+		var exitErr *exec.ExitError
+		switch {
+		case errors.As(err, &exitErr) && len(result.errors) == 0:
+			// A failure without listed problems, such as a broken config.
+			result.err = errors.New(strings.TrimSpace(string(output)))
+		case err != nil && !errors.As(err, &exitErr):
+			result.err = err
+		}
+		return result
+	}
+}
+
+// isCommitlintSummary reports whether a marked line is the "found N
+// problems" total rather than a problem.
+func isCommitlintSummary(line string) bool {
+	return strings.HasPrefix(strings.TrimSpace(line), "found ")
+}
+
+// lintChecks turns the latest commitlint run into checklist entries. The
+// commit waits while commitlint is running for the current message.
+func (m Model) lintChecks(message string) []check {
+	result := m.lintResult
+	if result == nil || result.message != message {
+		return []check{{"commitlint", checkPending, "running…"}}
+	}
+	if result.err != nil {
+		return []check{{"commitlint", checkFail, result.err.Error()}}
+	}
+	var checks []check
+	for _, problem := range result.errors {
+		checks = append(checks, check{"commitlint", checkFail, problem})
+	}
+	for _, problem := range result.warnings {
+		checks = append(checks, check{"commitlint", checkWarn, problem})
+	}
+	if len(checks) == 0 {
+		checks = append(checks, check{rule: "commitlint"})
+	}
+	return checks
+}
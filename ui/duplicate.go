@@ -0,0 +1,50 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// duplicatesLast reports whether the pending header is the subject of the
+// HEAD commit, leaving it in lastSubject. HEAD is read again each time, so
+// a commit made from elsewhere during the session counts. Amending keeps
+// the subject on purpose, and quick commits are meant to be repeated.
+func (m *Model) duplicatesLast() bool {
+	if m.dupAllowed || m.opts.Amend || m.quickCommit {
+		return false
+	}
+	last, err := m.repo.LastSubject()
+	if err != nil || last == "" {
+		return false
+	}
+	header, _, _ := strings.Cut(m.pendingMessage().Header(), "\n")
+	m.lastSubject = last
+	return strings.TrimSpace(header) == last
+}
+
+// updateConfirmDuplicate handles keys on the duplicate subject prompt.
+// Enter does nothing here, so a repeated keypress can't confirm it.
+func (m Model) updateConfirmDuplicate(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.CommitAnyway):
+		return m.startCommit()
+	case key.Matches(msg, m.keys.Back):
+		return m.enterState(stateConfirm)
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+// confirmDuplicateView renders the duplicate subject prompt.
+func (m Model) confirmDuplicateView() string {
+	s := titleStyle.Render("Same Subject as the Last Commit") + "\n"
+	s += warnStyle.Render("⚠ HEAD already has this subject:") + "\n"
+	s += itemStyle.Render(m.lastSubject) + "\n\n"
+	s += fmt.Sprintf("Press %s to commit it anyway, %s to go back or %s to quit\n", keyName(m.keys.CommitAnyway), keyName(m.keys.Back), keyName(m.keys.Quit))
+	s += m.hint("Pass --allow-duplicate or set allowDuplicate to skip this check")
+	return s
+}
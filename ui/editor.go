@@ -0,0 +1,125 @@
+package ui
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+
+	"StevenD2002/GoCommit/git"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// editorDoneMsg reports that the editor opened from the confirmation screen
+// exited. path holds the edited message.
+type editorDoneMsg struct {
+	path string
+	err  error
+}
+
+// editorCommand returns the user's editor from $VISUAL or $EDITOR, falling
+// back to vi. The variable may include arguments, as in "code --wait".
+func editorCommand() []string {
+	for _, name := range []string{"VISUAL", "EDITOR"} {
+		if fields := strings.Fields(os.Getenv(name)); len(fields) > 0 {
+			return fields
+		}
+	}
+	return []string{"vi"}
+}
+
+// openEditor suspends the TUI and opens the assembled message in the user's
+// editor. The result is committed as written once the editor exits.
+func (m Model) openEditor() (tea.Model, tea.Cmd) {
+	m.editorErr = nil
+	m.editorAborted = false
+	f, err := os.CreateTemp("", "gocommit-*.txt")
+	if err != nil {
+		m.editorErr = err
+		return m, nil
+	}
+	_, err = f.WriteString(m.message().String() + "\n")
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		m.editorErr = err
+		return m, nil
+	}
+
+	args := append(editorCommand(), f.Name())
+	cmd := exec.Command(args[0], args[1:]...)
+	return m, tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return editorDoneMsg{path: f.Name(), err: err}
+	})
+}
+
+// finishEditing commits the message written in the editor. An empty message
+// aborts, as it does for git, and leaves the confirmation screen as it was.
+func (m Model) finishEditing(msg editorDoneMsg) (tea.Model, tea.Cmd) {
+	data, err := os.ReadFile(msg.path)
+	os.Remove(msg.path)
+	if msg.err != nil {
+		m.editorErr = msg.err
+		return m, nil
+	}
+	if err != nil {
+		m.editorErr = err
+		return m, nil
+	}
+	edited := strings.TrimSpace(string(data))
+	if edited == "" {
+		m.editorAborted = true
+		return m, nil
+	}
+
+	m.edited = edited
+	if m.opts.DryRun {
+		m.dryRunOutput = edited
+		return m, tea.Quit
+	}
+	if m.protected {
+		return m.enterState(stateConfirmBranch)
+	}
+	return m.commit()
+}
+
+// amendDoneMsg reports that the git commit --amend run after committing
+// exited.
+type amendDoneMsg struct {
+	err error
+}
+
+// amendInEditor suspends the TUI and reopens the new commit with git commit
+// --amend, which starts git's editor on its message.
+func (m Model) amendInEditor() tea.Cmd {
+	cmd := exec.Command("git", git.AmendArgs(m.opts)...)
+	cmd.Dir = m.repoRoot
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return amendDoneMsg{err: err}
+	})
+}
+
+// finishAmend picks up the refined commit, records it in the history and
+// carries on to the push or the summary. When git fails, as it does for an emptied message, the commit is
+// kept as it was.
+func (m Model) finishAmend(msg amendDoneMsg) (tea.Model, tea.Cmd) {
+	m.amendErr = msg.err
+	if msg.err == nil {
+		m.commitSHA, _ = m.repo.HeadSHA()
+		if raw, err := m.repo.HeadMessage(); err == nil {
+			m.amended = strings.TrimSpace(raw)
+		}
+	}
+	if !m.quickCommit {
+		if m.amended != "" {
+			m.recordCommit(m.amended)
+		} else {
+			m.recordCommit(m.pendingMessage().String())
+		}
+	}
+	if m.push {
+		return m.beginPush(false)
+	}
+	return m.enterState(stateCommitted)
+}
@@ -0,0 +1,48 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// updateCommitFailed handles keys on the screen shown when git commit
+// fails. The composed message is kept, so the commit can be retried as is,
+// for example after fixing what a hook complained about, or edited first.
+func (m Model) updateCommitFailed(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.RetryCommit):
+		m.err = nil
+		m.errOutput = ""
+		return m.startCommit()
+	case key.Matches(msg, m.keys.Back):
+		m.err = nil
+		m.errOutput = ""
+		if m.quickCommit {
+			// Go back to the type list, where the quick commit started.
+			m.quickCommit = false
+			m.fixupTarget = nil
+			return m.enterState(stateSelectType)
+		}
+		return m.enterState(stateEnterMessage)
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+// commitFailedView renders git's error, the message that was being
+// committed and the ways out.
+func (m Model) commitFailedView() string {
+	s := m.errorView() + "\n"
+	s += titleStyle.Render("Message") + "\n"
+	s += itemStyle.Render(displayHeader(m.pendingMessage())) + "\n\n"
+	back := "to edit the message"
+	if m.quickCommit {
+		back = "to go back to the type list"
+	}
+	s += fmt.Sprintf("Press %s to retry, %s %s or %s to quit\n", keyName(m.keys.RetryCommit), keyName(m.keys.Back), back, keyName(m.keys.Quit))
+	s += m.hint("Nothing was committed; the message is kept until you quit")
+	return s
+}
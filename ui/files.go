@@ -0,0 +1,315 @@
+package ui
+
+import (
+	"fmt"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// collapsedFiles is how many staged files are listed before the panel is
+// expanded; expanded, it shows at most maxFileRows and scrolls.
+const (
+	collapsedFiles = 5
+	maxFileRows    = 10
+)
+
+// fileCountTitle returns the header of the staged files panel.
+func fileCountTitle(n int) string {
+	switch n {
+	case 0:
+		return "No files staged"
+	case 1:
+		return "1 file staged"
+	}
+	return fmt.Sprintf("%d files staged", n)
+}
+
+// fileLines renders the staged files with their line counts.
+func (m Model) fileLines(files []string) string {
+	var lines []string
+	for _, file := range files {
+		if m.excluded[file] {
+			lines = append(lines, itemStyle.Render(mutedStyle.Render(file+" (left staged)")))
+			continue
+		}
+		lines = append(lines, itemStyle.Render(file+m.statView(file)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// hasMoreFiles reports whether the staged files don't fit the collapsed
+// panel.
+func (m Model) hasMoreFiles() bool {
+	return len(m.stagedFiles) > collapsedFiles
+}
+
+// toggleFiles expands or collapses the staged files panel and refits the
+// rest of the screen to its new height.
+func (m Model) toggleFiles() Model {
+	m.filesExpanded = !m.filesExpanded
+	if m.filesExpanded {
+		content := m.expandedFileLines()
+		m.filesPanel.Height = min(strings.Count(content, "\n")+1, maxFileRows)
+		m.filesPanel.SetContent(content)
+		m.filesPanel.GotoTop()
+	}
+	if m.height > 0 {
+		m.resize(m.width, m.height)
+	}
+	return m
+}
+
+// expandedFileLines returns the content of the expanded panel: the files
+// under their group headers when grouped, else the plain list.
+func (m Model) expandedFileLines() string {
+	if !m.groupsFiles() {
+		return m.fileLines(m.stagedFiles)
+	}
+	var lines []string
+	for _, g := range groupFiles(m.stagedFiles) {
+		lines = append(lines, m.groupHeader("▾ ", g))
+		for _, line := range strings.Split(m.fileLines(g.files), "\n") {
+			lines = append(lines, "  "+line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// filesView renders the staged files panel, ending in a newline.
+func (m Model) filesView() string {
+	if !m.hasMoreFiles() {
+		if len(m.stagedFiles) == 0 {
+			return ""
+		}
+		return m.fileLines(m.stagedFiles) + "\n"
+	}
+	if m.filesExpanded {
+		s := m.filesPanel.View() + "\n"
+		if m.filesPanel.TotalLineCount() > maxFileRows {
+			s += itemStyle.Render(mutedStyle.Render(fmt.Sprintf("%3.f%% (%s/%s to scroll, %s to collapse)",
+				m.filesPanel.ScrollPercent()*100, keyName(m.keys.FilesUp), keyName(m.keys.FilesDown), keyName(m.keys.ToggleFiles)))) + "\n"
+		}
+		return s
+	}
+	if m.groupsFiles() {
+		return m.collapsedGroupsView()
+	}
+	s := m.fileLines(m.stagedFiles[:collapsedFiles]) + "\n"
+	more := len(m.stagedFiles) - collapsedFiles
+	s += itemStyle.Render(mutedStyle.Render(fmt.Sprintf("… and %d more (%s to show all)", more, keyName(m.keys.ToggleFiles)))) + "\n"
+	return s
+}
+
+// fileGroup is the staged files under one top-level directory.
+type fileGroup struct {
+	// dir is empty for files in the repository root.
+	dir   string
+	files []string
+}
+
+// fileArea returns the top-level directory a file belongs to. Under
+// directories that hold packages, such as packages/ or internal/, it is the
+// package directory instead, so a monorepo splits by package. Files in the
+// root return "".
+func fileArea(file string) string {
+	dirs := strings.Split(path.Dir(file), "/")
+	switch {
+	case dirs[0] == ".":
+		return ""
+	case slices.Contains(scopeContainers, dirs[0]) && len(dirs) > 1:
+		return dirs[0] + "/" + dirs[1]
+	}
+	return dirs[0]
+}
+
+// groupFiles splits files by fileArea, keeping the order in which each
+// area first appears.
+func groupFiles(files []string) []fileGroup {
+	var groups []fileGroup
+	index := make(map[string]int)
+	for _, file := range files {
+		dir := fileArea(file)
+		i, ok := index[dir]
+		if !ok {
+			i = len(groups)
+			index[dir] = i
+			groups = append(groups, fileGroup{dir: dir})
+		}
+		groups[i].files = append(groups[i].files, file)
+	}
+	return groups
+}
+
+// groupsFiles reports whether the staged files are shown grouped by
+// directory: when they don't fit the collapsed panel and span more than one
+// directory.
+func (m Model) groupsFiles() bool {
+	return m.hasMoreFiles() && len(groupFiles(m.stagedFiles)) > 1
+}
+
+// groupHeader renders the line naming a group and its file count.
+func (m Model) groupHeader(marker string, g fileGroup) string {
+	name := g.dir + "/"
+	if g.dir == "" {
+		name = "(root)"
+	}
+	count := "1 file"
+	if len(g.files) != 1 {
+		count = fmt.Sprintf("%d files", len(g.files))
+	}
+	return itemStyle.Render(marker + name + mutedStyle.Render(" "+count))
+}
+
+// collapsedGroupsView lists the groups with their counts, without the files.
+func (m Model) collapsedGroupsView() string {
+	groups := groupFiles(m.stagedFiles)
+	var s string
+	for _, g := range groups[:min(len(groups), collapsedFiles)] {
+		s += m.groupHeader("▸ ", g) + "\n"
+	}
+	hint := fmt.Sprintf("%s to show the files", keyName(m.keys.ToggleFiles))
+	if more := len(groups) - collapsedFiles; more > 0 {
+		hint = fmt.Sprintf("… and %d more directories (%s)", more, hint)
+	} else {
+		hint = "(" + hint + ")"
+	}
+	s += itemStyle.Render(mutedStyle.Render(hint)) + "\n"
+	return s
+}
+
+// unstagedCountTitle returns the header of the not staged section.
+func unstagedCountTitle(n int) string {
+	if n == 1 {
+		return "1 unstaged change"
+	}
+	return fmt.Sprintf("%d unstaged changes", n)
+}
+
+// notStagedView lists, dimmed, the changes the commit will leave behind so
+// a forgotten file stands out. It is empty when the work tree is clean.
+func (m Model) notStagedView() string {
+	if len(m.notStaged) == 0 {
+		return ""
+	}
+	// The leading space lines the header up with the padded titles.
+	s := "\n " + warnStyle.Render(unstagedCountTitle(len(m.notStaged))) + mutedStyle.Render(" (not staged)") + "\n"
+	for _, change := range m.notStaged[:min(len(m.notStaged), collapsedFiles)] {
+		line := change.Path
+		if change.Untracked {
+			line += " (untracked)"
+		}
+		s += itemStyle.Render(mutedStyle.Render(line)) + "\n"
+	}
+	if more := len(m.notStaged) - collapsedFiles; more > 0 {
+		s += itemStyle.Render(mutedStyle.Render(fmt.Sprintf("… and %d more", more))) + "\n"
+	}
+	return s
+}
+
+// openFileSelection shows the screen for committing only some of the staged
+// files, starting from the current choice.
+func (m Model) openFileSelection() (tea.Model, tea.Cmd) {
+	m.fileDraft = maps.Clone(m.excluded)
+	if m.fileDraft == nil {
+		m.fileDraft = make(map[string]bool)
+	}
+	m.fileErr = ""
+	if unstaged, err := m.repo.UnstagedFiles(); err == nil {
+		m.unstaged = make(map[string]bool, len(unstaged))
+		for _, file := range unstaged {
+			m.unstaged[file] = true
+		}
+	}
+	return m.enterState(stateSelectFiles)
+}
+
+// updateSelectFiles handles keys on the file selection screen. Files are
+// kept in fileDraft until the choice is confirmed.
+func (m Model) updateSelectFiles(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+	case key.Matches(msg, m.keys.Up):
+		m.fileCursor = max(m.fileCursor-1, 0)
+	case key.Matches(msg, m.keys.Down):
+		m.fileCursor = min(m.fileCursor+1, len(m.stagedFiles)-1)
+	case key.Matches(msg, m.keys.ToggleFile):
+		file := m.stagedFiles[m.fileCursor]
+		m.fileDraft[file] = !m.fileDraft[file]
+	case key.Matches(msg, m.keys.ToggleAllFiles):
+		all := len(m.selectedFiles(m.fileDraft)) == len(m.stagedFiles)
+		for _, file := range m.stagedFiles {
+			m.fileDraft[file] = all
+		}
+	case key.Matches(msg, m.keys.Next):
+		selected := m.selectedFiles(m.fileDraft)
+		if len(selected) == 0 {
+			m.fileErr = "Select at least one file to commit"
+			return m, nil
+		}
+		m.excluded = m.fileDraft
+		m.opts.Paths = nil
+		if len(selected) < len(m.stagedFiles) {
+			m.opts.Paths = selected
+		}
+		return m.enterState(stateSelectType)
+	case key.Matches(msg, m.keys.Back):
+		return m.enterState(stateSelectType)
+	}
+	m.fileErr = ""
+	return m, nil
+}
+
+// selectedFiles returns the staged files not marked in excluded.
+func (m Model) selectedFiles(excluded map[string]bool) []string {
+	var files []string
+	for _, file := range m.stagedFiles {
+		if !excluded[file] {
+			files = append(files, file)
+		}
+	}
+	return files
+}
+
+// selectFilesView renders the file selection screen, scrolling so the
+// cursor stays within maxFileRows lines.
+func (m Model) selectFilesView() string {
+	s := titleStyle.Render("Choose Files to Commit") + "\n"
+
+	start := max(m.fileCursor-maxFileRows+1, 0)
+	end := min(start+maxFileRows, len(m.stagedFiles))
+	if start > 0 {
+		s += itemStyle.Render(mutedStyle.Render(fmt.Sprintf("↑ %d more", start))) + "\n"
+	}
+	for i := start; i < end; i++ {
+		file := m.stagedFiles[i]
+		box := "[x] "
+		if m.fileDraft[file] {
+			box = "[ ] "
+		}
+		line := box + file + m.statView(file)
+		if m.unstaged[file] && !m.fileDraft[file] {
+			line += " " + warnStyle.Render("(unstaged changes will be committed too)")
+		}
+		if i == m.fileCursor {
+			s += "  > " + line + "\n"
+		} else {
+			s += itemStyle.Render(line) + "\n"
+		}
+	}
+	if end < len(m.stagedFiles) {
+		s += itemStyle.Render(mutedStyle.Render(fmt.Sprintf("↓ %d more", len(m.stagedFiles)-end))) + "\n"
+	}
+	if m.fileErr != "" {
+		s += "\n" + breakingStyle.Render(m.fileErr) + "\n"
+	}
+	s += "\n" + pageStyle.Render(fmt.Sprintf("%s toggles a file, %s toggles all, %s to confirm, %s to cancel",
+		keyName(m.keys.ToggleFile), keyName(m.keys.ToggleAllFiles), keyName(m.keys.Next), keyName(m.keys.Back))) + "\n"
+	s += pageStyle.Render("Files left out stay staged for the next commit")
+	return s
+}
@@ -0,0 +1,93 @@
+package ui
+
+import (
+	"fmt"
+
+	"StevenD2002/GoCommit/git"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxFixupCommits is how many recent commits are offered as fixup targets.
+const maxFixupCommits = 30
+
+// openFixup lists the recent commits to choose the target of a fixup commit.
+func (m Model) openFixup() (tea.Model, tea.Cmd) {
+	commits, err := m.repo.RecentCommits(maxFixupCommits)
+	m.fixupCommits = commits
+	m.fixupErr = ""
+	if err != nil {
+		m.fixupErr = "Could not list commits: " + err.Error()
+		if _, headErr := m.repo.HeadSHA(); headErr != nil {
+			// git log fails on a branch without commits.
+			m.fixupErr = "There are no commits to fix up yet"
+		}
+	}
+	m.fixupCursor = 0
+	return m.enterState(stateSelectFixup)
+}
+
+// updateSelectFixup handles keys on the fixup target screen. Picking a
+// commit commits the staged changes right away, like the quick WIP commit.
+func (m Model) updateSelectFixup(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+	case key.Matches(msg, m.keys.Up):
+		m.fixupCursor = max(m.fixupCursor-1, 0)
+	case key.Matches(msg, m.keys.Down):
+		m.fixupCursor = min(m.fixupCursor+1, len(m.fixupCommits)-1)
+	case key.Matches(msg, m.keys.Next) && len(m.fixupCommits) > 0:
+		target := m.fixupCommits[m.fixupCursor]
+		m.fixupTarget = &target
+		if m.opts.DryRun {
+			m.dryRunOutput = m.fixupMessage().String()
+			return m, tea.Quit
+		}
+		m.quickCommit = true
+		if m.protected {
+			return m.enterState(stateConfirmBranch)
+		}
+		return m.startCommit()
+	case key.Matches(msg, m.keys.Back):
+		return m.enterState(stateSelectType)
+	}
+	return m, nil
+}
+
+// fixupMessage returns the "fixup! <subject>" message that git rebase
+// --autosquash folds into the target commit.
+func (m Model) fixupMessage() git.Message {
+	return git.Message{Subject: "fixup! " + m.fixupTarget.Subject}
+}
+
+// selectFixupView renders the recent commits, scrolling so the cursor stays
+// within maxFileRows lines.
+func (m Model) selectFixupView() string {
+	s := titleStyle.Render("Fix Up a Commit") + "\n"
+	if m.fixupErr != "" {
+		s += breakingStyle.Render(m.fixupErr) + "\n\n"
+		return s + pageStyle.Render("Press "+keyName(m.keys.Back)+" to go back")
+	}
+
+	start := max(m.fixupCursor-maxFileRows+1, 0)
+	end := min(start+maxFileRows, len(m.fixupCommits))
+	if start > 0 {
+		s += itemStyle.Render(mutedStyle.Render(fmt.Sprintf("↑ %d more", start))) + "\n"
+	}
+	for i := start; i < end; i++ {
+		c := m.fixupCommits[i]
+		line := mutedStyle.Render(c.SHA[:min(len(c.SHA), shortSHALength)]) + " " + c.Subject
+		if i == m.fixupCursor {
+			s += "  > " + line + "\n"
+		} else {
+			s += itemStyle.Render(line) + "\n"
+		}
+	}
+	if end < len(m.fixupCommits) {
+		s += itemStyle.Render(mutedStyle.Render(fmt.Sprintf("↓ %d more", len(m.fixupCommits)-end))) + "\n"
+	}
+	s += "\n" + pageStyle.Render(fmt.Sprintf("%s commits the staged changes as a fixup! of the chosen commit, %s to go back", keyName(m.keys.Next), keyName(m.keys.Back))) + "\n"
+	s += pageStyle.Render("Fold them in later with git rebase -i --autosquash")
+	return s
+}
@@ -0,0 +1,155 @@
+package ui
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"StevenD2002/GoCommit/git"
+)
+
+const (
+	// maxRecentCoauthors caps how many co-authors are remembered across runs.
+	maxRecentCoauthors = 20
+	// maxRecentSubjects caps how many subjects are remembered per repository.
+	maxRecentSubjects = 50
+)
+
+// history is data remembered between runs, stored as JSON in the user's
+// config directory.
+type history struct {
+	Coauthors []string                `json:"coauthors"`
+	Repos     map[string]*repoHistory `json:"repos,omitempty"`
+}
+
+// repoHistory is the history kept for a single repository, keyed by its
+// root directory.
+type repoHistory struct {
+	Subjects []subjectEntry `json:"subjects"`
+}
+
+type subjectEntry struct {
+	Type    string `json:"type"`
+	Subject string `json:"subject"`
+}
+
+func historyPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "gocommit", "history.json"), nil
+}
+
+// loadHistory reads the history file. A missing or unreadable file simply
+// yields an empty history since it only powers suggestions.
+func loadHistory() history {
+	var h history
+	path, err := historyPath()
+	if err != nil {
+		return h
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return h
+	}
+	_ = json.Unmarshal(data, &h)
+	return h
+}
+
+func (h history) save() error {
+	path, err := historyPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// addCoauthors moves the given co-authors to the front of the recent list.
+func (h *history) addCoauthors(coauthors []string) {
+	for _, c := range coauthors {
+		h.Coauthors = slices.DeleteFunc(h.Coauthors, func(existing string) bool { return existing == c })
+		h.Coauthors = append([]string{c}, h.Coauthors...)
+	}
+	if len(h.Coauthors) > maxRecentCoauthors {
+		h.Coauthors = h.Coauthors[:maxRecentCoauthors]
+	}
+}
+
+// addSubject records a committed subject as the most recent for the repo.
+func (h *history) addSubject(repo, commitType, subject string) {
+	if repo == "" || subject == "" {
+		return
+	}
+	if h.Repos == nil {
+		h.Repos = make(map[string]*repoHistory)
+	}
+	r := h.Repos[repo]
+	if r == nil {
+		r = &repoHistory{}
+		h.Repos[repo] = r
+	}
+
+	entry := subjectEntry{Type: commitType, Subject: subject}
+	r.Subjects = slices.DeleteFunc(r.Subjects, func(e subjectEntry) bool { return e == entry })
+	r.Subjects = append([]subjectEntry{entry}, r.Subjects...)
+	if len(r.Subjects) > maxRecentSubjects {
+		r.Subjects = r.Subjects[:maxRecentSubjects]
+	}
+}
+
+// lastType returns the commit type of the most recent commit in the repo,
+// or "" when there is none.
+func (h history) lastType(repo string) string {
+	r := h.Repos[repo]
+	if r == nil || len(r.Subjects) == 0 {
+		return ""
+	}
+	return r.Subjects[0].Type
+}
+
+// subjects returns the recent subjects for the repo, most recent first. Only
+// subjects used with commitType are returned unless there are none.
+func (h history) subjects(repo, commitType string) []string {
+	r := h.Repos[repo]
+	if r == nil {
+		return nil
+	}
+
+	var all, typed []string
+	for _, e := range r.Subjects {
+		if !slices.Contains(all, e.Subject) {
+			all = append(all, e.Subject)
+		}
+		if e.Type == commitType && !slices.Contains(typed, e.Subject) {
+			typed = append(typed, e.Subject)
+		}
+	}
+	if len(typed) > 0 {
+		return typed
+	}
+	return all
+}
+
+// recordCommit remembers the co-authors and the subject of a commit made in
+// the TUI. The subject is parsed from raw, the message as committed, since
+// the editor may have changed it from what was typed.
+func (m *Model) recordCommit(raw string) {
+	msg, _ := git.ParseConventional(raw)
+	commitType := msg.Type
+	if commitType == "" {
+		// Gitmoji and free-form headers don't name the type.
+		commitType = m.selectedType
+	}
+	m.history.addCoauthors(m.coauthors)
+	m.history.addSubject(m.repoRoot, commitType, msg.Subject)
+	_ = m.history.save()
+}
@@ -0,0 +1,36 @@
+package ui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// idleMsg fires when the idle timeout may have run out.
+type idleMsg struct{}
+
+// idleCmd wakes the model after d.
+func idleCmd(d time.Duration) tea.Cmd {
+	return tea.Tick(d, func(time.Time) tea.Msg {
+		return idleMsg{}
+	})
+}
+
+// checkIdle quits without committing once no key or mouse input has arrived
+// for the idle timeout. Input only records its time, so the timer is
+// rescheduled here for whatever is left. A running commit, push or pull
+// request is never interrupted.
+func (m Model) checkIdle() (tea.Model, tea.Cmd) {
+	left := m.idleTimeout - time.Since(m.lastInput)
+	if left > 0 || m.committing || m.pushing || m.openingPR {
+		return m, idleCmd(max(left, time.Second))
+	}
+	m.timedOut = !m.committed
+	return m, tea.Quit
+}
+
+// TimedOut reports whether the TUI quit for lack of input before anything
+// was committed.
+func (m Model) TimedOut() bool {
+	return m.timedOut
+}
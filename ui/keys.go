@@ -0,0 +1,253 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+)
+
+// keyMap holds the bindings handled by the model. Navigation keys owned by
+// the list and viewport are included so the help overlay can document them.
+type keyMap struct {
+	Up             key.Binding
+	Down           key.Binding
+	Filter         key.Binding
+	NextPage       key.Binding
+	PrevPage       key.Binding
+	GoToStart      key.Binding
+	GoToEnd        key.Binding
+	Scroll         key.Binding
+	Suggestions    key.Binding
+	Next           key.Binding
+	Back           key.Binding
+	Diff           key.Binding
+	SelectFiles    key.Binding
+	ToggleFile     key.Binding
+	ToggleAllFiles key.Binding
+	ToggleFiles    key.Binding
+	ToggleMinimal  key.Binding
+	FilesUp        key.Binding
+	FilesDown      key.Binding
+	QuickCommit    key.Binding
+	Fixup          key.Binding
+	FinishBody     key.Binding
+	SkipBody       key.Binding
+	ToggleBreaking key.Binding
+	ToggleTicket   key.Binding
+	ToggleNoVerify key.Binding
+	TogglePush     key.Binding
+	EditAuthor     key.Binding
+	EditMessage    key.Binding
+	EditType       key.Binding
+	EditScope      key.Binding
+	EditSubject    key.Binding
+	EditBody       key.Binding
+	CopyMessage    key.Binding
+	ToggleSignoff  key.Binding
+	Undo           key.Binding
+	RetryCommit    key.Binding
+	CommitAnyway   key.Binding
+	RetryPush      key.Binding
+	SetUpstream    key.Binding
+	Help           key.Binding
+	Quit           key.Binding
+	ForceQuit      key.Binding
+}
+
+func defaultKeyMap() keyMap {
+	return keyMap{
+		Up:             key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("↑/k", "move up")),
+		Down:           key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("↓/j", "move down")),
+		Filter:         key.NewBinding(key.WithKeys("/"), key.WithHelp("/", "filter types")),
+		NextPage:       key.NewBinding(key.WithKeys("right", "pgdown"), key.WithHelp("→/pgdn", "next page")),
+		PrevPage:       key.NewBinding(key.WithKeys("left", "pgup"), key.WithHelp("←/pgup", "previous page")),
+		GoToStart:      key.NewBinding(key.WithKeys("home"), key.WithHelp("home", "go to start")),
+		GoToEnd:        key.NewBinding(key.WithKeys("end"), key.WithHelp("end", "go to end")),
+		Scroll:         key.NewBinding(key.WithKeys("up", "down", "pgup", "pgdown"), key.WithHelp("↑/↓/pgup/pgdn", "scroll")),
+		Suggestions:    key.NewBinding(key.WithKeys("up", "down", "tab"), key.WithHelp("↑/↓/tab", "suggestions")),
+		Next:           key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "continue")),
+		Back:           key.NewBinding(key.WithKeys("esc", "shift+tab"), key.WithHelp("esc", "back")),
+		Diff:           key.NewBinding(key.WithKeys("d"), key.WithHelp("d", "toggle diff")),
+		SelectFiles:    key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "choose files")),
+		ToggleFile:     key.NewBinding(key.WithKeys(" "), key.WithHelp("space", "toggle file")),
+		ToggleAllFiles: key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "toggle all")),
+		ToggleFiles:    key.NewBinding(key.WithKeys("ctrl+f"), key.WithHelp("ctrl+f", "toggle staged files")),
+		ToggleMinimal:  key.NewBinding(key.WithKeys("ctrl+o"), key.WithHelp("ctrl+o", "toggle minimal mode")),
+		FilesUp:        key.NewBinding(key.WithKeys("shift+up"), key.WithHelp("shift+↑", "scroll files up")),
+		FilesDown:      key.NewBinding(key.WithKeys("shift+down"), key.WithHelp("shift+↓", "scroll files down")),
+		QuickCommit:    key.NewBinding(key.WithKeys("w"), key.WithHelp("w", "quick wip commit")),
+		Fixup:          key.NewBinding(key.WithKeys("f"), key.WithHelp("f", "fixup commit")),
+		FinishBody:     key.NewBinding(key.WithKeys("ctrl+s"), key.WithHelp("ctrl+s", "finish body")),
+		SkipBody:       key.NewBinding(key.WithKeys("tab"), key.WithHelp("tab", "skip body")),
+		ToggleBreaking: key.NewBinding(key.WithKeys("ctrl+x"), key.WithHelp("ctrl+x", "toggle breaking")),
+		ToggleTicket:   key.NewBinding(key.WithKeys("ctrl+t"), key.WithHelp("ctrl+t", "toggle ticket prefix")),
+		ToggleNoVerify: key.NewBinding(key.WithKeys("n"), key.WithHelp("n", "toggle --no-verify")),
+		TogglePush:     key.NewBinding(key.WithKeys("p"), key.WithHelp("p", "toggle push")),
+		EditAuthor:     key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "set author")),
+		EditMessage:    key.NewBinding(key.WithKeys("e"), key.WithHelp("e", "edit in $EDITOR")),
+		EditType:       key.NewBinding(key.WithKeys("1"), key.WithHelp("1", "edit type")),
+		EditScope:      key.NewBinding(key.WithKeys("2"), key.WithHelp("2", "edit scope")),
+		EditSubject:    key.NewBinding(key.WithKeys("3"), key.WithHelp("3", "edit subject")),
+		EditBody:       key.NewBinding(key.WithKeys("4"), key.WithHelp("4", "edit body")),
+		CopyMessage:    key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "copy message")),
+		ToggleSignoff:  key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "toggle signoff")),
+		Undo:           key.NewBinding(key.WithKeys("u"), key.WithHelp("u", "undo commit")),
+		RetryCommit:    key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "retry commit")),
+		CommitAnyway:   key.NewBinding(key.WithKeys("y"), key.WithHelp("y", "commit anyway")),
+		RetryPush:      key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "retry push")),
+		SetUpstream:    key.NewBinding(key.WithKeys("u"), key.WithHelp("u", "push -u origin")),
+		Help:           key.NewBinding(key.WithKeys("?", "f1"), key.WithHelp("?/f1", "toggle help")),
+		Quit:           key.NewBinding(key.WithKeys("q"), key.WithHelp("q", "quit")),
+		ForceQuit:      key.NewBinding(key.WithKeys("ctrl+c"), key.WithHelp("ctrl+c", "quit")),
+	}
+}
+
+// bindings returns the bindings that can be remapped in the config, by
+// their action name. Scroll and Suggestions only document keys owned by the
+// viewport and text inputs, so they are left out.
+func (k *keyMap) bindings() map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"up":             &k.Up,
+		"down":           &k.Down,
+		"filter":         &k.Filter,
+		"nextPage":       &k.NextPage,
+		"prevPage":       &k.PrevPage,
+		"goToStart":      &k.GoToStart,
+		"goToEnd":        &k.GoToEnd,
+		"next":           &k.Next,
+		"back":           &k.Back,
+		"diff":           &k.Diff,
+		"selectFiles":    &k.SelectFiles,
+		"toggleFile":     &k.ToggleFile,
+		"toggleAllFiles": &k.ToggleAllFiles,
+		"toggleFiles":    &k.ToggleFiles,
+		"toggleMinimal":  &k.ToggleMinimal,
+		"filesUp":        &k.FilesUp,
+		"filesDown":      &k.FilesDown,
+		"quickCommit":    &k.QuickCommit,
+		"fixup":          &k.Fixup,
+		"finishBody":     &k.FinishBody,
+		"skipBody":       &k.SkipBody,
+		"toggleBreaking": &k.ToggleBreaking,
+		"toggleTicket":   &k.ToggleTicket,
+		"toggleNoVerify": &k.ToggleNoVerify,
+		"togglePush":     &k.TogglePush,
+		"editAuthor":     &k.EditAuthor,
+		"editMessage":    &k.EditMessage,
+		"editType":       &k.EditType,
+		"editScope":      &k.EditScope,
+		"editSubject":    &k.EditSubject,
+		"editBody":       &k.EditBody,
+		"copyMessage":    &k.CopyMessage,
+		"toggleSignoff":  &k.ToggleSignoff,
+		"undo":           &k.Undo,
+		"retryCommit":    &k.RetryCommit,
+		"commitAnyway":   &k.CommitAnyway,
+		"retryPush":      &k.RetryPush,
+		"setUpstream":    &k.SetUpstream,
+		"help":           &k.Help,
+		"quit":           &k.Quit,
+		"forceQuit":      &k.ForceQuit,
+	}
+}
+
+// remap replaces the keys of the actions set in the config. Actions that
+// aren't mentioned keep their defaults.
+func (k *keyMap) remap(overrides map[string][]string) error {
+	bindings := k.bindings()
+	for action, keys := range overrides {
+		b, ok := bindings[action]
+		if !ok {
+			return fmt.Errorf("unknown key action %q", action)
+		}
+		if len(keys) == 0 {
+			return fmt.Errorf("no keys for action %q", action)
+		}
+		b.SetKeys(keys...)
+		b.SetHelp(strings.Join(keys, "/"), b.Help().Desc)
+	}
+	return nil
+}
+
+// keyName returns the keys of b as the hints name them, such as "Enter",
+// "Ctrl+X" or "d", so remapped keys show up in the hints too.
+func keyName(b key.Binding) string {
+	names := strings.Split(b.Help().Key, "/")
+	for i, name := range names {
+		if name == "" {
+			// The key is "/" itself.
+			return b.Help().Key
+		}
+		if utf8.RuneCountInString(name) == 1 {
+			continue
+		}
+		parts := strings.Split(name, "+")
+		for j, part := range parts {
+			r, size := utf8.DecodeRuneInString(part)
+			parts[j] = string(unicode.ToUpper(r)) + part[size:]
+		}
+		names[i] = strings.Join(parts, "+")
+	}
+	return strings.Join(names, "/")
+}
+
+// applyListKeys makes a list navigate with the model's bindings. Leaving the
+// first list with Back quits, as Quit does. The list's own paging keys
+// include letters such as d, f and u, which would page silently once the
+// actions using them are remapped, so only the configurable ones apply.
+func (k keyMap) applyListKeys(l *list.Model) {
+	l.KeyMap.CursorUp = k.Up
+	l.KeyMap.CursorDown = k.Down
+	l.KeyMap.NextPage = k.NextPage
+	l.KeyMap.PrevPage = k.PrevPage
+	l.KeyMap.GoToStart = k.GoToStart
+	l.KeyMap.GoToEnd = k.GoToEnd
+	l.KeyMap.Filter = k.Filter
+	l.KeyMap.Quit.SetKeys(slices.Concat(k.Quit.Keys(), k.Back.Keys())...)
+}
+
+// helpBindings returns the bindings that apply to the current state, grouped
+// into columns for the help overlay.
+func (m Model) helpBindings() [][]key.Binding {
+	k := m.keys
+	switch m.state {
+	case stateSelectTemplate:
+		return [][]key.Binding{{k.Up, k.Down, k.Next}, {k.PrevPage, k.NextPage, k.GoToStart, k.GoToEnd}, {k.Help, k.Quit}}
+	case stateSelectType:
+		if m.showDiff {
+			return [][]key.Binding{{k.Scroll}, {k.Diff, k.Quit}}
+		}
+		return [][]key.Binding{{k.Up, k.Down, k.Filter}, {k.PrevPage, k.NextPage, k.GoToStart, k.GoToEnd}, {k.Next, k.Diff, k.SelectFiles, k.QuickCommit, k.Fixup, k.ToggleBreaking}, {k.Help, k.Quit}}
+	case stateEnterMessage:
+		bindings := []key.Binding{k.Next, k.Back, k.Suggestions}
+		if !m.freeForm {
+			bindings = append(bindings, k.ToggleBreaking)
+		}
+		if m.ticket != "" {
+			bindings = append(bindings, k.ToggleTicket)
+		}
+		return [][]key.Binding{bindings, {k.Help, k.ForceQuit}}
+	case stateEnterScope, stateEnterTrailers:
+		return [][]key.Binding{{k.Next, k.Back, k.Suggestions}, {k.Help, k.ForceQuit}}
+	case stateEnterBody:
+		return [][]key.Binding{{k.FinishBody, k.SkipBody, k.Back}, {k.Help, k.ForceQuit}}
+	case stateConfirm:
+		return [][]key.Binding{{k.Next, k.Back, k.ToggleNoVerify, k.TogglePush, k.EditAuthor, k.EditMessage, k.CopyMessage, k.ToggleSignoff}, {k.EditType, k.EditScope, k.EditSubject, k.EditBody}, {k.Help, k.Quit}}
+	case statePush:
+		return [][]key.Binding{{k.RetryPush, k.SetUpstream}, {k.Help, k.Quit}}
+	case stateCommitted:
+		return [][]key.Binding{{k.Undo, k.Next}, {k.Help, k.Quit}}
+	case stateCommitFailed:
+		return [][]key.Binding{{k.RetryCommit, k.Back}, {k.Help, k.Quit}}
+	case stateConfirmDuplicate:
+		return [][]key.Binding{{k.CommitAnyway, k.Back}, {k.Help, k.Quit}}
+	case stateSelectFiles:
+		return [][]key.Binding{{k.Up, k.Down, k.ToggleFile, k.ToggleAllFiles}, {k.Next, k.Back}, {k.Help, k.Quit}}
+	}
+	return [][]key.Binding{{k.Next, k.Back}, {k.Help, k.ForceQuit}}
+}
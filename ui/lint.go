@@ -0,0 +1,213 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// imperativeVerbs are common verbs that start commit subjects. Only words
+// derived from these are flagged, since English suffixes are too irregular
+// to guess at in general.
+var imperativeVerbs = map[string]bool{
+	"add": true, "adjust": true, "allow": true, "apply": true, "avoid": true,
+	"bump": true, "change": true, "clean": true, "cleanup": true, "configure": true,
+	"convert": true, "copy": true, "create": true, "delete": true, "deprecate": true,
+	"disable": true, "document": true, "drop": true, "enable": true, "ensure": true,
+	"extract": true, "fix": true, "handle": true, "implement": true, "improve": true,
+	"include": true, "initialize": true, "introduce": true, "merge": true, "migrate": true,
+	"move": true, "optimize": true, "prevent": true, "refactor": true, "reformat": true,
+	"remove": true, "rename": true, "reorder": true, "replace": true, "restore": true,
+	"revert": true, "rewrite": true, "set": true, "show": true, "simplify": true,
+	"skip": true, "split": true, "stop": true, "support": true, "switch": true,
+	"test": true, "tidy": true, "update": true, "upgrade": true, "use": true,
+	"validate": true, "wrap": true,
+}
+
+// imperativeStem returns the imperative form of word when it looks like a
+// past tense, gerund or third person form of a known verb.
+func imperativeStem(word string) (string, bool) {
+	var candidates []string
+	switch {
+	case strings.HasSuffix(word, "ied"):
+		candidates = append(candidates, strings.TrimSuffix(word, "ied")+"y")
+	case strings.HasSuffix(word, "ies"):
+		candidates = append(candidates, strings.TrimSuffix(word, "ies")+"y")
+	case strings.HasSuffix(word, "ing"):
+		stem := strings.TrimSuffix(word, "ing")
+		candidates = append(candidates, stem, stem+"e", undouble(stem))
+	case strings.HasSuffix(word, "ed"):
+		stem := strings.TrimSuffix(word, "ed")
+		candidates = append(candidates, stem, stem+"e", undouble(stem))
+	case strings.HasSuffix(word, "es"):
+		candidates = append(candidates, strings.TrimSuffix(word, "es"), strings.TrimSuffix(word, "s"))
+	case strings.HasSuffix(word, "s"):
+		candidates = append(candidates, strings.TrimSuffix(word, "s"))
+	}
+
+	for _, c := range candidates {
+		if c != word && imperativeVerbs[c] {
+			return c, true
+		}
+	}
+	return "", false
+}
+
+// undouble strips a doubled final consonant, as in "dropp" from "dropped".
+func undouble(stem string) string {
+	n := len(stem)
+	if n >= 2 && stem[n-1] == stem[n-2] {
+		return stem[:n-1]
+	}
+	return stem
+}
+
+// moodWarning returns an advisory when the subject doesn't start with an
+// imperative verb, or "" when it looks fine.
+func moodWarning(subject string) string {
+	fields := strings.Fields(subject)
+	if len(fields) == 0 {
+		return ""
+	}
+	word := strings.ToLower(strings.Trim(fields[0], ".,:;!?"))
+	if stem, ok := imperativeStem(word); ok {
+		return fmt.Sprintf("Use the imperative mood: %q instead of %q", stem, word)
+	}
+	return ""
+}
+
+// checkLevel is the outcome of a rule in the confirmation checklist.
+type checkLevel int
+
+const (
+	checkPass checkLevel = iota
+	checkWarn
+	// checkPending blocks the commit until a check running in the
+	// background reports back.
+	checkPending
+	checkFail
+)
+
+// check is the result of one rule. Failed checks block the commit, and
+// warnings have to be acknowledged before committing.
+type check struct {
+	rule   string
+	level  checkLevel
+	detail string
+}
+
+// checks runs every rule that applies to the assembled message.
+func (m Model) checks() []check {
+	msg := m.message()
+	header := msg.Header()
+	var checks []check
+
+	if strings.TrimSpace(msg.Subject) == "" {
+		checks = append(checks, check{"Subject", checkFail, "the subject is empty"})
+	}
+
+	length := check{rule: "Header length"}
+	switch n := utf8.RuneCountInString(header); {
+	case n > m.maxHeaderLen:
+		length.level = checkFail
+		length.detail = fmt.Sprintf("%d characters; the limit is %d", n, m.maxHeaderLen)
+	case n > recommendedHeaderLength:
+		length.level = checkWarn
+		length.detail = fmt.Sprintf("%d characters; %d or fewer is recommended", n, recommendedHeaderLength)
+	default:
+		length.detail = fmt.Sprintf("%d characters", n)
+	}
+	checks = append(checks, length)
+
+	if m.headerPattern != nil {
+		pattern := check{rule: "Header pattern", detail: m.headerPattern.String()}
+		if !m.headerPattern.MatchString(header) {
+			pattern.level = checkFail
+			pattern.detail = "doesn't match " + m.headerPattern.String()
+		}
+		checks = append(checks, pattern)
+	}
+
+	period := check{rule: "Trailing period"}
+	if strings.HasSuffix(msg.Subject, ".") {
+		period.level = checkWarn
+		period.detail = "the subject ends with a period"
+	}
+	checks = append(checks, period)
+
+	if m.checkMood {
+		mood := check{rule: "Imperative mood"}
+		if warning := moodWarning(msg.Subject); warning != "" {
+			mood.level = checkWarn
+			mood.detail = strings.TrimPrefix(warning, "Use the imperative mood: ")
+		}
+		checks = append(checks, mood)
+	}
+
+	if m.speller != nil {
+		spelling := check{rule: "Spelling"}
+		if typos := m.speller.misspelled(msg.Subject); len(typos) > 0 {
+			spelling.level = checkWarn
+			spelling.detail = "possible typos: " + strings.Join(typos, ", ")
+		}
+		checks = append(checks, spelling)
+	}
+
+	if m.lineBudget > 0 {
+		size := check{rule: "Commit size"}
+		n := m.changedLines()
+		size.detail = fmt.Sprintf("%d changed lines", n)
+		if n > m.lineBudget {
+			size.level = checkWarn
+			size.detail = fmt.Sprintf("%d changed lines, over the budget of %d; consider splitting the commit", n, m.lineBudget)
+		}
+		checks = append(checks, size)
+	}
+
+	if m.commitlint {
+		checks = append(checks, m.lintChecks(msg.String())...)
+	}
+	return checks
+}
+
+// changedLines returns the insertions plus deletions of the files being
+// committed. Binary files don't count.
+func (m Model) changedLines() int {
+	var n int
+	for _, file := range m.selectedFiles(m.excluded) {
+		stat := m.fileStats[file]
+		n += stat.Added + stat.Deleted
+	}
+	return n
+}
+
+// worstCheck returns the most severe level among checks.
+func worstCheck(checks []check) checkLevel {
+	worst := checkPass
+	for _, c := range checks {
+		worst = max(worst, c.level)
+	}
+	return worst
+}
+
+// checksView renders the checklist shown on the confirmation step.
+func checksView(checks []check) string {
+	s := titleStyle.Render("Checks") + "\n"
+	for _, c := range checks {
+		line := c.rule
+		if c.detail != "" {
+			line += ": " + c.detail
+		}
+		switch c.level {
+		case checkPass:
+			s += addedStyle.Render("✔ ") + line + "\n"
+		case checkWarn:
+			s += warnStyle.Render("! "+line) + "\n"
+		case checkPending:
+			s += mutedStyle.Render("… "+line) + "\n"
+		case checkFail:
+			s += breakingStyle.Render("✘ "+line) + "\n"
+		}
+	}
+	return s
+}
@@ -0,0 +1,36 @@
+package ui
+
+import "fmt"
+
+// minimalHeight is the terminal height under which minimal mode turns on by
+// itself, unless it was toggled by hand.
+const minimalHeight = 20
+
+// minimalView replaces the repository header and staged files panel with a
+// single line in minimal mode.
+func (m Model) minimalView() string {
+	if m.committed {
+		return ""
+	}
+	return mutedStyle.Render(fmt.Sprintf("%s (%s to show)", fileCountTitle(len(m.stagedFiles)), keyName(m.keys.ToggleMinimal))) + "\n"
+}
+
+// toggleMinimal shows or hides the staged files panel and hints. Once
+// toggled by hand, the mode no longer follows the terminal height.
+func (m Model) toggleMinimal() Model {
+	m.minimal = !m.minimal
+	m.minimalSet = true
+	if m.height > 0 {
+		m.resize(m.width, m.height)
+	}
+	return m
+}
+
+// hint renders the hint line at the bottom of a step, which minimal mode
+// leaves out.
+func (m Model) hint(text string) string {
+	if m.minimal {
+		return ""
+	}
+	return pageStyle.Render(text)
+}
@@ -0,0 +1,1909 @@
+// Package ui implements the interactive commit TUI.
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"StevenD2002/GoCommit/config"
+	"StevenD2002/GoCommit/git"
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	appStyle   = lipgloss.NewStyle().Padding(1, 2)
+	titleStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#FFFDF5")).
+			Background(lipgloss.Color("#25A065")).
+			Padding(0, 1)
+	itemStyle       = lipgloss.NewStyle().PaddingLeft(4)
+	pageStyle       = lipgloss.NewStyle().PaddingLeft(2).Foreground(lipgloss.Color("#888888"))
+	breakingStyle   = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FF5F5F"))
+	errorTitleStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#FFFDF5")).
+			Background(lipgloss.Color("#D9534F")).
+			Padding(0, 1)
+	breakingBadge = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("#FFFDF5")).
+			Background(lipgloss.Color("#FF5F5F")).
+			Padding(0, 1)
+	addedStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("#5FD75F"))
+	removedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#FF5F5F"))
+	hunkStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("#5FAFFF"))
+	mutedStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("#888888"))
+	warnStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("#FFD75F"))
+)
+
+// recommendedHeaderLength is the length past which the live character
+// counter turns yellow; it turns red past the configured limit.
+const recommendedHeaderLength = 50
+
+// maxDiffLines caps how much of the staged diff is loaded into the preview.
+const maxDiffLines = 5000
+
+// commitType is a selectable commit type. The emoji is kept apart from the
+// title so it can be shown in the list without being committed.
+type commitType struct {
+	title, desc, emoji string
+	// gitmoji is what gets committed in gitmoji mode.
+	gitmoji string
+	// subject is the type's subject template, if any.
+	subject string
+	// keywords are extra words the filter matches.
+	keywords []string
+}
+
+func (c commitType) Title() string       { return c.emoji + c.title }
+func (c commitType) Description() string { return c.desc }
+
+// FilterValue starts with the displayed title, emoji included, so the runes
+// the list highlights line up with what it shows. The description and
+// keywords are included so types can be found by what they are for.
+func (c commitType) FilterValue() string {
+	return strings.Join(append([]string{c.Title(), c.desc}, c.keywords...), " ")
+}
+
+// substringFilter keeps the items containing the filter term, ignoring case,
+// in their original order. The list's default fuzzy matching lets letters
+// scattered across unrelated types match.
+func substringFilter(term string, targets []string) []list.Rank {
+	term = strings.ToLower(term)
+	n := utf8.RuneCountInString(term)
+	var ranks []list.Rank
+	for i, target := range targets {
+		target = strings.ToLower(target)
+		idx := strings.Index(target, term)
+		if idx < 0 {
+			continue
+		}
+		start := utf8.RuneCountInString(target[:idx])
+		matched := make([]int, n)
+		for j := range matched {
+			matched[j] = start + j
+		}
+		ranks = append(ranks, list.Rank{Index: i, MatchedIndexes: matched})
+	}
+	return ranks
+}
+
+const (
+	stateSelectTemplate = iota
+	stateSelectType
+	stateEnterScope
+	stateEnterMessage
+	stateEnterBody
+	stateEnterBreaking
+	stateEnterCoauthors
+	stateEnterRefs
+	stateEnterTrailers
+	stateConfirm
+	stateEnterAuthor
+	stateConfirmBranch
+	statePush
+	stateSelectFiles
+	stateCommitted
+	stateSelectFixup
+	stateCommitFailed
+	stateConfirmDuplicate
+)
+
+// Model is the Bubble Tea model driving the commit flow.
+type Model struct {
+	repo          *git.Repo
+	committer     git.Committer
+	stagedFiles   []string
+	fileStats     map[string]git.FileStat
+	inProgress    string
+	commitSHA     string
+	warningsAcked bool
+	identity      string
+	signing       git.SigningMethod
+	undoErr       error
+	excluded      map[string]bool
+	fileDraft     map[string]bool
+	fileCursor    int
+	fileErr       string
+	unstaged      map[string]bool
+	notStaged     []git.UnstagedChange
+	detached      bool
+	idleTimeout   time.Duration
+	lineBudget    int
+	wrapBody      int
+	commitlint    bool
+	lintResult    *commitlintDoneMsg
+	lastInput     time.Time
+	timedOut      bool
+	commitTypes   list.Model
+	scopeInput    textinput.Model
+	textInput     textinput.Model
+	bodyInput     textarea.Model
+	breakingInput textinput.Model
+	selectedType  string
+	selectedEmoji string
+	typeGitmoji   string
+	selectedScope string
+	branchScope   string
+	headerFormat  string
+	body          string
+	isBreaking    bool
+	breakingDesc  string
+	coauthorInput textinput.Model
+	coauthors     []string
+	coauthorErr   string
+	trailerInput  textinput.Model
+	trailerErr    string
+	refInput      textinput.Model
+	refs          []string
+	refErr        string
+	history       history
+	diffView      viewport.Model
+	filesPanel    viewport.Model
+	filesExpanded bool
+	showDiff      bool
+	diffLoaded    bool
+	state         int
+	err           error
+	errOutput     string
+	width         int
+	height        int
+	noEmoji       bool
+	opts          git.CommitOptions
+	freeForm      bool
+	maxHeaderLen  int
+	headerPattern *regexp.Regexp
+	messageErr    string
+	ticket        string
+	prependTicket bool
+	dryRunOutput  string
+	repoRoot      string
+	keys          keyMap
+	help          help.Model
+	showHelp      bool
+	checkMood     bool
+	speller       *speller
+	subjectRules  config.SubjectRules
+	spinner       spinner.Model
+	committing    bool
+	committed     bool
+	wip           config.Wip
+	quickCommit   bool
+	jumpBack      bool
+	editAfter     bool
+	minimal       bool
+	minimalCfg    bool
+	minimalSet    bool
+	amended       string
+	amendErr      error
+	fixupCommits  []git.LogEntry
+	fixupCursor   int
+	fixupErr      string
+	fixupTarget   *git.LogEntry
+	protected     bool
+	dupAllowed    bool
+	lastSubject   string
+	lastScope     string
+	scopes        []scopeCandidate
+	recentScopes  []recentScope
+	scopeDraft    string
+	itemRows      int
+	authorInput   textinput.Model
+	authorErr     string
+	copied        bool
+	copyErr       error
+	branchInput   textinput.Model
+	branchErr     string
+	branch        string
+	push          bool
+	pushing       bool
+	pushCh        chan tea.Msg
+	pushOutput    []string
+	pushErr       error
+	openPR        bool
+	openingPR     bool
+	prOutput      string
+	prErr         error
+	prSkipped     string
+	templates     list.Model
+	hasTemplates  bool
+	trailers      []string
+	edited        string
+	editorErr     error
+	editorAborted bool
+}
+
+// parseRefs splits a comma separated list of references and checks each one
+// looks like a footer.
+func parseRefs(value string) ([]string, error) {
+	var refs []string
+	for _, ref := range strings.Split(value, ",") {
+		ref = strings.TrimSpace(ref)
+		if ref == "" {
+			continue
+		}
+		if !git.RefPattern.MatchString(ref) {
+			return nil, fmt.Errorf("%q doesn't look like a reference such as \"Closes #123\" or \"Refs: JIRA-456\"", ref)
+		}
+		refs = append(refs, ref)
+	}
+	return refs, nil
+}
+
+// renderDiff colorizes a diff and truncates it to maxDiffLines so huge
+// changesets stay responsive.
+func renderDiff(raw string) string {
+	lines := strings.Split(strings.TrimRight(raw, "\n"), "\n")
+	truncated := len(lines) > maxDiffLines
+	if truncated {
+		lines = lines[:maxDiffLines]
+	}
+
+	for i, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+			// File headers stay uncolored.
+		case strings.HasPrefix(line, "+"):
+			lines[i] = addedStyle.Render(line)
+		case strings.HasPrefix(line, "-"):
+			lines[i] = removedStyle.Render(line)
+		case strings.HasPrefix(line, "@@"):
+			lines[i] = hunkStyle.Render(line)
+		}
+	}
+
+	diff := strings.Join(lines, "\n")
+	if truncated {
+		diff += fmt.Sprintf("\n\n… diff truncated after %d lines", maxDiffLines)
+	}
+	return diff
+}
+
+// commitDoneMsg reports the result of a background commit.
+type commitDoneMsg struct {
+	output string
+	err    error
+}
+
+// commitCmd runs the commit outside the update loop so the UI stays
+// responsive while hooks run.
+func commitCmd(committer git.Committer, msg git.Message, opts git.CommitOptions) tea.Cmd {
+	return func() tea.Msg {
+		output, err := committer.Commit(msg, opts)
+		return commitDoneMsg{output: output, err: err}
+	}
+}
+
+// Option changes how New sets up the model.
+type Option func(*Model)
+
+// WithCommitter makes the model commit through c instead of the repository,
+// so a fake can record the message and options.
+func WithCommitter(c git.Committer) Option {
+	return func(m *Model) { m.committer = c }
+}
+
+// New builds the TUI model for the repository.
+func New(cfg config.Config, opts git.CommitOptions, repo *git.Repo, options ...Option) (Model, error) {
+	if err := repo.Check(); err != nil {
+		return Model{}, err
+	}
+
+	stagedFiles, err := repo.StagedFiles()
+	if err != nil {
+		return Model{}, err
+	}
+	// Only shown as a reminder, so a failure just leaves the section out.
+	notStaged, _ := repo.UnstagedChanges()
+	// Only used to preview the Signed-off-by trailer.
+	identity, _ := repo.Identity()
+	var signing git.SigningMethod
+	if opts.Sign {
+		signing, _ = repo.Signing(opts.SigningKey)
+	}
+
+	stats, err := repo.StagedStats()
+	if err != nil {
+		return Model{}, err
+	}
+	fileStats := make(map[string]git.FileStat, len(stats))
+	for _, stat := range stats {
+		fileStats[stat.Path] = stat
+	}
+
+	var allCommitTypes []list.Item
+	for _, t := range cfg.ListedTypes() {
+		ct := commitType{title: t.Title, desc: t.Desc, emoji: cfg.EmojiFor(t), gitmoji: cfg.GitmojiFor(t), subject: t.Subject, keywords: t.Keywords}
+		if ct.gitmoji != "" {
+			// Show the emoji even when the shortcode is committed.
+			ct.emoji = config.GitmojiToEmoji(ct.gitmoji)
+		}
+		allCommitTypes = append(allCommitTypes, ct)
+	}
+
+	theme, err := cfg.Theme.Resolve()
+	if err != nil {
+		return Model{}, err
+	}
+	applyTheme(theme)
+
+	keys := defaultKeyMap()
+	if err := keys.remap(cfg.Keys); err != nil {
+		return Model{}, err
+	}
+
+	// Set up delegate for the list
+	delegate := list.NewDefaultDelegate()
+	delegate.ShowDescription = true
+	itemRows := delegate.Height() + delegate.Spacing()
+	delegate.Styles.SelectedTitle = delegate.Styles.SelectedTitle.Foreground(lipgloss.Color(theme.Selected)).BorderForeground(lipgloss.Color(theme.Selected))
+	delegate.Styles.SelectedDesc = delegate.Styles.SelectedDesc.Foreground(lipgloss.Color(theme.SelectedDesc))
+
+	// Configure list with proper dimensions; it scrolls once the types no
+	// longer fit and can be filtered by typing "/".
+	l := list.New(allCommitTypes, delegate, 60, 20)
+	l.SetShowStatusBar(false)
+	l.SetFilteringEnabled(true)
+	l.Filter = substringFilter
+	l.Styles.Title = titleStyle
+	l.Title = "Select commit type"
+	keys.applyListKeys(&l)
+
+	si := textinput.New()
+	si.Placeholder = "Enter scope (optional)"
+	si.CharLimit = 30
+	si.Width = 60
+	si.ShowSuggestions = true
+	scopes := scopeCandidates(stagedFiles)
+	var scopeNames []string
+	for _, c := range scopes {
+		scopeNames = append(scopeNames, c.scope)
+	}
+	var recent []recentScope
+	if entries, err := repo.RecentCommits(maxScopeCommits); err == nil {
+		recent = recentScopes(entries)
+	}
+	for _, r := range recent {
+		if !slices.Contains(scopeNames, r.scope) {
+			scopeNames = append(scopeNames, r.scope)
+		}
+	}
+	si.SetSuggestions(scopeNames)
+
+	ti := textinput.New()
+	ti.Placeholder = "Enter commit message"
+	// The subject alone can't be longer than the header limit.
+	ti.CharLimit = cfg.MaxHeaderLength
+	ti.Width = 60
+	ti.ShowSuggestions = true
+
+	ta := textarea.New()
+	ta.Placeholder = "Enter commit body (optional)"
+	ta.ShowLineNumbers = false
+	ta.SetWidth(60)
+	ta.SetHeight(6)
+
+	bi := textinput.New()
+	bi.Placeholder = "Describe the breaking change (optional)"
+	bi.Width = 60
+
+	hist := loadHistory()
+	ci := textinput.New()
+	ci.Placeholder = "Name <email> (leave empty to continue)"
+	ci.Width = 60
+	ci.ShowSuggestions = true
+	ci.SetSuggestions(hist.Coauthors)
+
+	repoRoot, _ := repo.Root()
+
+	ticketPattern, err := regexp.Compile(cfg.TicketPattern)
+	if err != nil {
+		return Model{}, fmt.Errorf("invalid ticketPattern: %w", err)
+	}
+	var headerPattern *regexp.Regexp
+	if cfg.HeaderPattern != "" {
+		if headerPattern, err = regexp.Compile(cfg.HeaderPattern); err != nil {
+			return Model{}, fmt.Errorf("invalid headerPattern: %w", err)
+		}
+	}
+
+	branch, _ := repo.CurrentBranch()
+	detached := repoRoot != "" && repo.Detached()
+	var ticket string
+	if branch != "" && branch != "HEAD" {
+		ticket = ticketPattern.FindString(branch)
+	}
+	// Protected branches such as main don't name a piece of work.
+	var branchScope string
+	if cfg.BranchScope && branch != "" && branch != "HEAD" && !slices.Contains(cfg.ProtectedBranches, branch) {
+		branchScopePattern, err := regexp.Compile(cfg.BranchScopePattern)
+		if err != nil {
+			return Model{}, fmt.Errorf("invalid branchScopePattern: %w", err)
+		}
+		branchScope = config.BranchScope(branchScopePattern, branch)
+	}
+	if branchScope != "" {
+		si.Placeholder = fmt.Sprintf("Enter scope (optional, defaults to %s from the branch)", branchScope)
+	}
+
+	ai := textinput.New()
+	ai.Placeholder = "Name <email> (leave empty to use your git identity)"
+	ai.Width = 60
+	ai.SetValue(opts.Author)
+
+	gi := textinput.New()
+	gi.Placeholder = "yes"
+	gi.CharLimit = 3
+	gi.Width = 10
+
+	ri := textinput.New()
+	ri.Placeholder = "Closes #123, Refs: JIRA-456 (optional)"
+	ri.Width = 60
+	if ticket != "" {
+		ri.SetValue("Refs: " + ticket)
+	}
+
+	vp := viewport.New(60, 20)
+
+	m := Model{
+		repo:          repo,
+		committer:     repo,
+		stagedFiles:   stagedFiles,
+		notStaged:     notStaged,
+		fileStats:     fileStats,
+		inProgress:    repo.InProgress(),
+		identity:      identity,
+		signing:       signing,
+		commitTypes:   l,
+		scopeInput:    si,
+		textInput:     ti,
+		bodyInput:     ta,
+		breakingInput: bi,
+		coauthorInput: ci,
+		refInput:      ri,
+		trailerInput:  newTrailerInput(),
+		history:       hist,
+		diffView:      vp,
+		filesPanel:    viewport.New(60, maxFileRows),
+		noEmoji:       cfg.NoEmoji,
+		opts:          opts,
+		repoRoot:      repoRoot,
+		keys:          keys,
+		help:          help.New(),
+		spinner:       spinner.New(spinner.WithSpinner(spinner.Dot)),
+		maxHeaderLen:  cfg.MaxHeaderLength,
+		headerPattern: headerPattern,
+		checkMood:     cfg.CheckMood,
+		wip:           cfg.Wip,
+		branch:        branch,
+		protected:     slices.Contains(cfg.ProtectedBranches, branch) || detached && cfg.ConfirmDetached,
+		detached:      detached,
+		idleTimeout:   time.Duration(cfg.IdleTimeout) * time.Second,
+		lineBudget:    cfg.MaxChangedLines,
+		wrapBody:      cfg.WrapBody,
+		editAfter:     cfg.EditAfter,
+		minimal:       cfg.Minimal,
+		minimalCfg:    cfg.Minimal,
+		dupAllowed:    cfg.AllowDuplicate,
+		commitlint:    cfg.Commitlint && commitlintAvailable(repoRoot),
+		lastInput:     time.Now(),
+		scopes:        scopes,
+		recentScopes:  recent,
+		itemRows:      itemRows,
+		authorInput:   ai,
+		branchInput:   gi,
+		push:          cfg.Push || cfg.OpenPR,
+		openPR:        cfg.OpenPR,
+		subjectRules:  cfg.SubjectRules(),
+		ticket:        ticket,
+		branchScope:   branchScope,
+		headerFormat:  cfg.HeaderFormat,
+		prependTicket: ticket != "" && cfg.PrependTicket,
+		templates:     newTemplateList(cfg.Templates, delegate, keys),
+		hasTemplates:  len(cfg.Templates) > 0 && !opts.Amend,
+	}
+	for _, option := range options {
+		option(&m)
+	}
+	m.state = m.firstState()
+	if cfg.SpellCheck {
+		m.speller = newSpeller(cfg.Words)
+	}
+	if !cfg.NoStickyType {
+		if i := m.typeIndex(hist.lastType(repoRoot)); i >= 0 {
+			m.commitTypes.Select(i)
+		}
+	}
+
+	// Consecutive commits often share a scope, so start from the last one.
+	if subject, err := repo.LastSubject(); err == nil {
+		if last, ok := git.ParseConventional(subject); ok {
+			m.lastScope = last.Scope
+			m.scopeInput.SetValue(last.Scope)
+		}
+	}
+
+	if opts.Amend {
+		raw, err := repo.HeadMessage()
+		if err != nil {
+			return Model{}, fmt.Errorf("reading HEAD commit: %w", err)
+		}
+		m.prefill(raw)
+	}
+
+	// Start a merge from the message git prepared instead of clobbering it.
+	if m.inProgress == "merge" && !opts.Amend {
+		if raw, err := repo.MergeMessage(); err == nil && raw != "" {
+			m.prefill(raw)
+		}
+	}
+
+	// Git's own "Revert "..."" header isn't conventional, so a revert starts
+	// from a generated revert: message instead.
+	if m.inProgress == "revert" && !opts.Amend {
+		if sha, err := repo.RevertHead(); err == nil {
+			if msg, err := repo.RevertMessage(sha); err == nil {
+				m.prefill(msg.String())
+			}
+		}
+	}
+
+	return m, nil
+}
+
+// prefill populates the flow from an existing commit message. Messages that
+// don't use one of the configured types open in free-form message mode.
+func (m *Model) prefill(raw string) {
+	msg, ok := git.ParseConventional(raw)
+
+	index := -1
+	if ok {
+		index = m.messageTypeIndex(msg)
+	}
+
+	m.body = msg.Body
+	m.bodyInput.SetValue(msg.Body)
+	m.coauthors = msg.Coauthors
+	m.refs = msg.Refs
+	m.refInput.SetValue(strings.Join(msg.Refs, ", "))
+	m.trailers = msg.Trailers
+	m.isBreaking = msg.Breaking
+	m.breakingDesc = msg.BreakingDesc
+	m.breakingInput.SetValue(msg.BreakingDesc)
+
+	if index < 0 {
+		m.freeForm = true
+		m.isBreaking = false
+		m.textInput.SetValue(strings.Split(strings.TrimSpace(raw), "\n")[0])
+		m.state = stateEnterMessage
+		m.textInput.Focus()
+		return
+	}
+
+	t := m.commitTypes.Items()[index].(commitType)
+	m.commitTypes.Select(index)
+	m.selectedType = t.title
+	m.selectedEmoji = t.emoji
+	m.typeGitmoji = t.gitmoji
+	m.selectedScope = msg.Scope
+	m.scopeInput.SetValue(msg.Scope)
+	subject := msg.Subject
+	if m.ticket != "" && strings.HasPrefix(subject, m.ticket+" ") {
+		// Keep the ticket as a toggle rather than part of the typed subject.
+		subject = strings.TrimPrefix(subject, m.ticket+" ")
+		m.prependTicket = true
+	}
+	m.textInput.SetValue(subject)
+}
+
+// typeIndex returns the position of the commit type named in a parsed
+// header, or -1 when it isn't configured.
+func (m Model) typeIndex(name string) int {
+	for i, item := range m.commitTypes.Items() {
+		t := item.(commitType)
+		if strings.EqualFold(name, t.title) || name == t.emoji+t.title {
+			return i
+		}
+	}
+	return -1
+}
+
+// messageTypeIndex returns the position of the commit type of a parsed
+// message, matching gitmoji headers by their gitmoji, or -1 when it isn't
+// configured.
+func (m Model) messageTypeIndex(msg git.Message) int {
+	if msg.Gitmoji == "" {
+		return m.typeIndex(msg.Type)
+	}
+	for i, item := range m.commitTypes.Items() {
+		t := item.(commitType)
+		if msg.Gitmoji == t.gitmoji || config.GitmojiToEmoji(msg.Gitmoji) == t.emoji {
+			return i
+		}
+	}
+	return -1
+}
+
+// Seed pre-populates the flow from a draft message for review. Drafts whose
+// first line doesn't parse as a header with a configured type are kept
+// whole as the body.
+func (m *Model) Seed(raw string) {
+	if msg, ok := git.ParseConventional(raw); ok && m.messageTypeIndex(msg) >= 0 {
+		m.prefill(raw)
+		return
+	}
+	m.body = strings.TrimSpace(raw)
+	m.bodyInput.SetValue(m.body)
+}
+
+func (m Model) Init() tea.Cmd {
+	if m.idleTimeout > 0 {
+		return tea.Batch(textinput.Blink, idleCmd(m.idleTimeout))
+	}
+	return textinput.Blink
+}
+
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.resize(msg.Width, msg.Height)
+
+	case spinner.TickMsg:
+		if m.committing || m.pushing || m.openingPR {
+			var cmd tea.Cmd
+			m.spinner, cmd = m.spinner.Update(msg)
+			return m, cmd
+		}
+		return m, nil
+
+	case commitDoneMsg:
+		m.committing = false
+		if msg.err != nil {
+			m.err = msg.err
+			m.errOutput = msg.output
+			return m.enterState(stateCommitFailed)
+		}
+		m.err = nil
+		m.committed = true
+		m.commitSHA, _ = m.repo.HeadSHA()
+		if m.editAfter {
+			// The history is recorded once the amended message is known.
+			return m, m.amendInEditor()
+		}
+		if !m.quickCommit {
+			m.recordCommit(m.pendingMessage().String())
+		}
+		if m.push {
+			return m.beginPush(false)
+		}
+		return m.enterState(stateCommitted)
+
+	case editorDoneMsg:
+		return m.finishEditing(msg)
+
+	case amendDoneMsg:
+		return m.finishAmend(msg)
+
+	case commitlintDoneMsg:
+		m.lintResult = &msg
+		return m, nil
+
+	case copyDoneMsg:
+		m.copied = msg.err == nil
+		m.copyErr = msg.err
+		return m, clearCopiedCmd()
+
+	case clearCopiedMsg:
+		m.copied = false
+		m.copyErr = nil
+		return m, nil
+
+	case pushLineMsg:
+		m.pushOutput = append(m.pushOutput, string(msg))
+		return m, waitForPush(m.pushCh)
+
+	case pushDoneMsg:
+		m.pushing = false
+		m.pushErr = msg.err
+		if msg.err == nil {
+			if m.openPR {
+				return m.beginPR()
+			}
+			return m, tea.Quit
+		}
+		if !m.repo.HasUpstream() {
+			m.pushOutput = append(m.pushOutput, fmt.Sprintf("The branch has no upstream; press %s to push with -u origin %s.", keyName(m.keys.SetUpstream), m.branch))
+		}
+		return m, nil
+
+	case prDoneMsg:
+		return m.finishPR(msg)
+
+	case idleMsg:
+		return m.checkIdle()
+
+	case tea.MouseMsg:
+		m.lastInput = time.Now()
+		return m.updateMouse(msg)
+
+	case tea.KeyMsg:
+		m.lastInput = time.Now()
+		if key.Matches(msg, m.keys.ForceQuit) {
+			return m, tea.Quit
+		}
+
+		// Keys are ignored while git is running.
+		if m.committing {
+			return m, nil
+		}
+
+		// While the filter input is active every key belongs to the list.
+		if m.state == stateSelectType && m.commitTypes.FilterState() == list.Filtering {
+			var cmd tea.Cmd
+			m.commitTypes, cmd = m.commitTypes.Update(msg)
+			return m, cmd
+		}
+
+		// Any key dismisses the help overlay.
+		if m.showHelp {
+			m.showHelp = false
+			return m, nil
+		}
+
+		// Printable keys in the text steps are typed rather than treated as
+		// shortcuts, so "q" or "?" can be part of a message.
+		typing := m.isTextState() && msg.Type == tea.KeyRunes
+
+		if key.Matches(msg, m.keys.Help) && !typing {
+			m.showHelp = true
+			return m, nil
+		}
+
+		if m.state == statePush {
+			return m.updatePush(msg)
+		}
+		if m.state == stateSelectFiles {
+			return m.updateSelectFiles(msg)
+		}
+		if m.state == stateCommitted {
+			return m.updateCommitted(msg)
+		}
+		if m.state == stateSelectFixup {
+			return m.updateSelectFixup(msg)
+		}
+		if m.state == stateCommitFailed {
+			return m.updateCommitFailed(msg)
+		}
+		if m.state == stateConfirmDuplicate {
+			return m.updateConfirmDuplicate(msg)
+		}
+
+		if m.state == stateSelectType && m.showDiff {
+			switch {
+			case key.Matches(msg, m.keys.Quit):
+				return m, tea.Quit
+			case key.Matches(msg, m.keys.Diff), key.Matches(msg, m.keys.Back):
+				m.showDiff = false
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.diffView, cmd = m.diffView.Update(msg)
+			return m, cmd
+		}
+
+		switch {
+		case key.Matches(msg, m.keys.Diff) && m.state == stateSelectType:
+			return m.toggleDiff(), nil
+
+		case key.Matches(msg, m.keys.SelectFiles) && m.state == stateSelectType && len(m.stagedFiles) > 1:
+			return m.openFileSelection()
+
+		case key.Matches(msg, m.keys.ToggleFiles) && m.hasMoreFiles() && !typing:
+			return m.toggleFiles(), nil
+
+		case key.Matches(msg, m.keys.ToggleMinimal) && !typing:
+			return m.toggleMinimal(), nil
+
+		case key.Matches(msg, m.keys.FilesUp) && m.filesExpanded && !typing:
+			m.filesPanel.ScrollUp(1)
+			return m, nil
+
+		case key.Matches(msg, m.keys.FilesDown) && m.filesExpanded && !typing:
+			m.filesPanel.ScrollDown(1)
+			return m, nil
+
+		case key.Matches(msg, m.keys.Fixup) && m.state == stateSelectType:
+			return m.openFixup()
+
+		case key.Matches(msg, m.keys.QuickCommit) && m.state == stateSelectType:
+			if m.opts.DryRun {
+				m.dryRunOutput = m.wipMessage().String()
+				return m, tea.Quit
+			}
+			m.quickCommit = true
+			m.fixupTarget = nil
+			if m.protected {
+				return m.enterState(stateConfirmBranch)
+			}
+			return m.startCommit()
+
+		case key.Matches(msg, m.keys.Quit) && !typing:
+			return m, tea.Quit
+
+		case key.Matches(msg, m.keys.SkipBody) && m.state == stateEnterBody:
+			m.body = ""
+			return m.advance()
+
+		case key.Matches(msg, m.keys.Next):
+			switch m.state {
+			case stateSelectTemplate, stateSelectType:
+				return m.pick()
+			case stateEnterScope:
+				// An empty scope is allowed and simply skips the step.
+				m.selectedScope = strings.TrimSpace(m.scopeInput.Value())
+				return m.advance()
+			case stateEnterMessage:
+				if m.message().Subject == "" {
+					m.messageErr = "The subject can't be empty"
+					return m, nil
+				}
+				header := m.message().Header()
+				if n := utf8.RuneCountInString(header); n > m.maxHeaderLen {
+					m.messageErr = fmt.Sprintf("Header is %d characters; the limit is %d", n, m.maxHeaderLen)
+					return m, nil
+				}
+				if m.headerPattern != nil && !m.headerPattern.MatchString(header) {
+					m.messageErr = fmt.Sprintf("%q doesn't match the required pattern %s", header, m.headerPattern)
+					return m, nil
+				}
+				m.messageErr = ""
+				return m.advance()
+			case stateEnterBreaking:
+				m.breakingDesc = strings.TrimSpace(m.breakingInput.Value())
+				return m.advance()
+			case stateEnterCoauthors:
+				value := strings.TrimSpace(m.coauthorInput.Value())
+				if value == "" {
+					// An empty entry finishes the step.
+					m.coauthorErr = ""
+					return m.advance()
+				}
+				if !git.IdentityPattern.MatchString(value) {
+					m.coauthorErr = "Co-authors must look like: Name <email@example.com>"
+					return m, nil
+				}
+				if !slices.Contains(m.coauthors, value) {
+					m.coauthors = append(m.coauthors, value)
+				}
+				m.coauthorErr = ""
+				m.coauthorInput.Reset()
+				return m, nil
+			case stateEnterRefs:
+				refs, err := parseRefs(m.refInput.Value())
+				if err != nil {
+					m.refErr = err.Error()
+					return m, nil
+				}
+				m.refs = refs
+				m.refErr = ""
+				return m.advance()
+			case stateEnterTrailers:
+				return m.addTrailer()
+			case stateConfirm:
+				switch worstCheck(m.checks()) {
+				case checkFail, checkPending:
+					return m, nil
+				case checkWarn:
+					if !m.warningsAcked {
+						m.warningsAcked = true
+						return m, nil
+					}
+				}
+				if m.opts.DryRun {
+					// main prints the message once the TUI has exited.
+					m.dryRunOutput = m.message().String()
+					return m, tea.Quit
+				}
+				if m.protected {
+					return m.enterState(stateConfirmBranch)
+				}
+				return m.commit()
+			case stateEnterAuthor:
+				value := strings.TrimSpace(m.authorInput.Value())
+				if value != "" && !git.IdentityPattern.MatchString(value) {
+					m.authorErr = "The author must look like: Name <email@example.com>"
+					return m, nil
+				}
+				m.authorErr = ""
+				m.opts.Author = value
+				return m.enterState(stateConfirm)
+			case stateConfirmBranch:
+				if !strings.EqualFold(strings.TrimSpace(m.branchInput.Value()), "yes") {
+					m.branchErr = fmt.Sprintf("Type yes to commit to %s", m.branch)
+					if m.detached {
+						m.branchErr = "Type yes to commit on the detached HEAD"
+					}
+					return m, nil
+				}
+				m.branchErr = ""
+				m.branchInput.Reset()
+				return m.commit()
+			}
+
+		case key.Matches(msg, m.keys.FinishBody) && m.state == stateEnterBody:
+			// Enter inserts newlines inside the textarea, so the body needs
+			// its own key to finish.
+			m.body = strings.TrimSpace(m.bodyInput.Value())
+			return m.advance()
+
+		case key.Matches(msg, m.keys.Back) && m.jumpBack:
+			return m.enterState(stateConfirm)
+
+		case key.Matches(msg, m.keys.Back) && m.state == stateConfirmBranch && m.quickCommit:
+			m.quickCommit = false
+			return m.enterState(stateSelectType)
+
+		case key.Matches(msg, m.keys.Back) && m.state > m.firstState():
+			return m.back()
+
+		case key.Matches(msg, m.keys.ToggleBreaking) && (m.state == stateSelectType || m.state == stateEnterMessage && !m.freeForm):
+			m.isBreaking = !m.isBreaking
+			return m, nil
+
+		case key.Matches(msg, m.keys.EditAuthor) && m.state == stateConfirm:
+			m.authorInput.SetValue(m.opts.Author)
+			m.authorErr = ""
+			return m.enterState(stateEnterAuthor)
+
+		case key.Matches(msg, m.keys.EditMessage) && m.state == stateConfirm:
+			return m.openEditor()
+
+		case key.Matches(msg, m.keys.EditType) && m.state == stateConfirm:
+			return m.jumpTo(stateSelectType)
+
+		case key.Matches(msg, m.keys.EditScope) && m.state == stateConfirm && !m.freeForm:
+			return m.jumpTo(stateEnterScope)
+
+		case key.Matches(msg, m.keys.EditSubject) && m.state == stateConfirm:
+			return m.jumpTo(stateEnterMessage)
+
+		case key.Matches(msg, m.keys.EditBody) && m.state == stateConfirm:
+			return m.jumpTo(stateEnterBody)
+
+		case key.Matches(msg, m.keys.CopyMessage) && m.state == stateConfirm:
+			return m, copyCmd(m.message().String())
+
+		case key.Matches(msg, m.keys.ToggleSignoff) && m.state == stateConfirm:
+			m.opts.Signoff = !m.opts.Signoff
+			return m, nil
+
+		case key.Matches(msg, m.keys.ToggleNoVerify) && m.state == stateConfirm:
+			m.opts.NoVerify = !m.opts.NoVerify
+			return m, nil
+
+		case key.Matches(msg, m.keys.TogglePush) && m.state == stateConfirm && !m.opts.DryRun:
+			m.push = !m.push
+			return m, nil
+
+		case key.Matches(msg, m.keys.ToggleTicket) && m.state == stateEnterMessage && m.ticket != "":
+			m.prependTicket = !m.prependTicket
+			return m, nil
+
+		case m.state == stateEnterScope && len(m.recentScopes) > 0 && (msg.Type == tea.KeyUp || msg.Type == tea.KeyDown):
+			if msg.Type == tea.KeyUp {
+				return m.moveScope(-1), nil
+			}
+			return m.moveScope(1), nil
+
+		case m.state == stateEnterTrailers && msg.Type == tea.KeyBackspace && m.trailerInput.Value() == "" && len(m.trailers) > 0:
+			return m.removeTrailer(), nil
+		}
+	}
+
+	switch m.state {
+	case stateSelectTemplate:
+		var cmd tea.Cmd
+		m.templates, cmd = m.templates.Update(msg)
+		return m, cmd
+	case stateSelectType:
+		var cmd tea.Cmd
+		m.commitTypes, cmd = m.commitTypes.Update(msg)
+		return m, cmd
+	case stateEnterScope:
+		var cmd tea.Cmd
+		m.scopeInput, cmd = m.scopeInput.Update(msg)
+		return m, cmd
+	case stateEnterMessage:
+		before := m.textInput.Value()
+		var cmd tea.Cmd
+		m.textInput, cmd = m.textInput.Update(msg)
+		if m.textInput.Value() != before {
+			// The error was about the subject as it was.
+			m.messageErr = ""
+		}
+		return m, cmd
+	case stateEnterBody:
+		var cmd tea.Cmd
+		m.bodyInput, cmd = m.bodyInput.Update(msg)
+		return m, cmd
+	case stateEnterBreaking:
+		var cmd tea.Cmd
+		m.breakingInput, cmd = m.breakingInput.Update(msg)
+		return m, cmd
+	case stateEnterCoauthors:
+		var cmd tea.Cmd
+		m.coauthorInput, cmd = m.coauthorInput.Update(msg)
+		return m, cmd
+	case stateEnterRefs:
+		var cmd tea.Cmd
+		m.refInput, cmd = m.refInput.Update(msg)
+		return m, cmd
+	case stateEnterTrailers:
+		before := m.trailerInput.Value()
+		var cmd tea.Cmd
+		m.trailerInput, cmd = m.trailerInput.Update(msg)
+		if m.trailerInput.Value() != before {
+			m.trailerErr = ""
+		}
+		return m, cmd
+	case stateEnterAuthor:
+		var cmd tea.Cmd
+		m.authorInput, cmd = m.authorInput.Update(msg)
+		return m, cmd
+	case stateConfirmBranch:
+		var cmd tea.Cmd
+		m.branchInput, cmd = m.branchInput.Update(msg)
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+// pick chooses the highlighted template or commit type and moves on.
+func (m Model) pick() (tea.Model, tea.Cmd) {
+	switch m.state {
+	case stateSelectTemplate:
+		if i, ok := m.templates.SelectedItem().(templateItem); ok {
+			m.applyTemplate(i.template)
+			return m.advance()
+		}
+	case stateSelectType:
+		if i, ok := m.commitTypes.SelectedItem().(commitType); ok {
+			m.selectedType = i.title
+			m.selectedEmoji = i.emoji
+			m.typeGitmoji = i.gitmoji
+			m.freeForm = false
+			return m.advance()
+		}
+	}
+	return m, nil
+}
+
+// isTextState reports whether the current step is a text input.
+func (m Model) isTextState() bool {
+	switch m.state {
+	case stateSelectTemplate, stateSelectType, stateConfirm, statePush, stateSelectFiles, stateCommitted, stateSelectFixup, stateCommitFailed, stateConfirmDuplicate:
+		return false
+	}
+	return true
+}
+
+// toggleDiff shows the staged diff preview, loading it on first use.
+func (m Model) toggleDiff() Model {
+	if !m.diffLoaded {
+		raw, err := m.repo.StagedDiff()
+		diff := renderDiff(raw)
+		if err != nil {
+			diff = fmt.Sprintf("Could not load diff: %v", err)
+		}
+		m.diffView.SetContent(diff)
+		m.diffLoaded = true
+	}
+	m.showDiff = !m.showDiff
+	return m
+}
+
+// stepEnabled reports whether a state is part of the flow for the current
+// commit. Optional steps are skipped by advance and back.
+func (m Model) stepEnabled(state int) bool {
+	switch state {
+	case stateSelectTemplate:
+		return m.hasTemplates
+	case stateEnterScope:
+		return !m.freeForm
+	case stateEnterBreaking:
+		return m.isBreaking && !m.freeForm
+	case stateEnterAuthor, stateSelectFiles, stateCommitted:
+		// Only reached from the confirmation step and the type list.
+		return false
+	}
+	return true
+}
+
+// jumpTo edits a single field from the confirmation step; finishing or
+// leaving that step returns to the confirmation.
+func (m Model) jumpTo(state int) (tea.Model, tea.Cmd) {
+	m.jumpBack = true
+	return m.enterState(state)
+}
+
+// jumpHint lists the keys editing a single field from the confirmation.
+func (m Model) jumpHint() string {
+	k := m.keys
+	if m.freeForm {
+		return fmt.Sprintf("Press %s to pick a type, %s to edit the subject or %s the body",
+			keyName(k.EditType), keyName(k.EditSubject), keyName(k.EditBody))
+	}
+	return fmt.Sprintf("Press %s to change the type, %s the scope, %s the subject or %s the body",
+		keyName(k.EditType), keyName(k.EditScope), keyName(k.EditSubject), keyName(k.EditBody))
+}
+
+// advance moves to the next enabled step, or back to the confirmation when
+// the step was opened from there.
+func (m Model) advance() (tea.Model, tea.Cmd) {
+	if m.jumpBack {
+		return m.enterState(stateConfirm)
+	}
+	next := m.state + 1
+	for next < stateConfirm && !m.stepEnabled(next) {
+		next++
+	}
+	return m.enterState(next)
+}
+
+// back returns to the previous step. Inputs keep their values so nothing
+// typed so far is lost.
+func (m Model) back() (tea.Model, tea.Cmd) {
+	prev := m.state - 1
+	for prev > m.firstState() && !m.stepEnabled(prev) {
+		prev--
+	}
+	return m.enterState(prev)
+}
+
+// firstState is where the flow starts: the template picker when templates
+// are configured, otherwise the type list.
+func (m Model) firstState() int {
+	if m.hasTemplates {
+		return stateSelectTemplate
+	}
+	return stateSelectType
+}
+
+// enterState switches to a state and focuses its input, if any.
+func (m Model) enterState(state int) (tea.Model, tea.Cmd) {
+	m.scopeInput.Blur()
+	m.textInput.Blur()
+	m.bodyInput.Blur()
+	m.breakingInput.Blur()
+	m.coauthorInput.Blur()
+	m.refInput.Blur()
+	m.trailerInput.Blur()
+	m.branchInput.Blur()
+	m.authorInput.Blur()
+
+	m.state = state
+	switch state {
+	case stateConfirm:
+		m.warningsAcked = false
+		m.jumpBack = false
+		// Going back from the branch confirmation drops an edited message.
+		m.edited = ""
+		if message := m.message().String(); m.commitlint && (m.lintResult == nil || m.lintResult.message != message) {
+			return m, commitlintCmd(m.repoRoot, message)
+		}
+	case stateEnterScope:
+		return m, m.scopeInput.Focus()
+	case stateEnterMessage:
+		// Suggest subjects previously used with this type; up and down
+		// cycle through them and tab accepts one.
+		m.textInput.SetSuggestions(m.history.subjects(m.repoRoot, m.selectedType))
+		return m, m.textInput.Focus()
+	case stateEnterBody:
+		return m, m.bodyInput.Focus()
+	case stateEnterBreaking:
+		return m, m.breakingInput.Focus()
+	case stateEnterCoauthors:
+		return m, m.coauthorInput.Focus()
+	case stateEnterRefs:
+		return m, m.refInput.Focus()
+	case stateEnterTrailers:
+		return m, m.trailerInput.Focus()
+	case stateEnterAuthor:
+		return m, m.authorInput.Focus()
+	case stateConfirmBranch:
+		return m, m.branchInput.Focus()
+	}
+	return m, nil
+}
+
+// commit starts the commit, first asking for confirmation when the subject
+// repeats the last commit's, which usually means Enter was pressed twice or
+// the session is stale.
+func (m Model) commit() (tea.Model, tea.Cmd) {
+	if m.duplicatesLast() {
+		return m.enterState(stateConfirmDuplicate)
+	}
+	return m.startCommit()
+}
+
+// startCommit runs git commit for the pending message. Hooks can take a
+// while, so it commits in the background and keeps the spinner going until
+// commitDoneMsg arrives.
+func (m Model) startCommit() (tea.Model, tea.Cmd) {
+	opts := m.opts
+	if m.edited != "" {
+		opts.ViaFile = true
+	}
+	m.committing = true
+	return m, tea.Batch(m.spinner.Tick, commitCmd(m.committer, m.pendingMessage(), opts))
+}
+
+// pendingMessage returns the message about to be committed: the fixup or
+// quick WIP message, the one written in the editor, or the assembled one.
+func (m Model) pendingMessage() git.Message {
+	switch {
+	case m.quickCommit && m.fixupTarget != nil:
+		return m.fixupMessage()
+	case m.quickCommit:
+		return m.wipMessage()
+	case m.edited != "":
+		// Without a type the subject is committed verbatim.
+		return git.Message{Subject: m.edited}
+	}
+	return m.message()
+}
+
+// subjectTemplate returns the subject template of the selected type.
+func (m Model) subjectTemplate() string {
+	for _, item := range m.commitTypes.Items() {
+		if t := item.(commitType); t.title == m.selectedType {
+			return t.subject
+		}
+	}
+	return ""
+}
+
+// wipMessage builds the quick work-in-progress commit message.
+func (m Model) wipMessage() git.Message {
+	msg := git.Message{Type: m.wip.Type, Subject: m.wip.Message, HeaderFormat: m.headerFormat}
+	for _, item := range m.commitTypes.Items() {
+		if t := item.(commitType); t.title == m.wip.Type {
+			msg.Gitmoji = t.gitmoji
+			if !m.noEmoji && t.gitmoji == "" {
+				msg.Emoji = t.emoji
+			}
+		}
+	}
+	return msg
+}
+
+// message assembles the commit message from the current model state.
+func (m Model) message() git.Message {
+	subject := m.subjectRules.Normalize(m.textInput.Value())
+	if m.freeForm {
+		return git.Message{
+			Subject:   subject,
+			Body:      git.WrapBody(m.body, m.wrapBody),
+			Coauthors: m.coauthors,
+			Refs:      m.refs,
+			Trailers:  m.trailers,
+		}
+	}
+
+	emoji := m.selectedEmoji
+	if m.noEmoji || m.typeGitmoji != "" {
+		emoji = ""
+	}
+	var ticket string
+	if m.prependTicket {
+		ticket = m.ticket
+	}
+	subject = config.ApplySubject(m.subjectTemplate(), subject)
+	return git.Message{
+		Emoji:        emoji,
+		Gitmoji:      m.typeGitmoji,
+		Type:         m.selectedType,
+		HeaderFormat: m.headerFormat,
+		Scope:        m.scope(),
+		Ticket:       ticket,
+		Subject:      subject,
+		Body:         git.WrapBody(m.body, m.wrapBody),
+		Breaking:     m.isBreaking,
+		BreakingDesc: m.breakingDesc,
+		Coauthors:    m.coauthors,
+		Refs:         m.refs,
+		Trailers:     m.trailers,
+	}
+}
+
+// scope returns the entered scope or, when none was entered, the one
+// derived from the branch name.
+func (m Model) scope() string {
+	if m.selectedScope == "" {
+		return m.branchScope
+	}
+	return m.selectedScope
+}
+
+// stagedView renders the repository header and the staged files shown
+// above every step.
+func (m Model) stagedView() string {
+	if m.minimal {
+		return m.minimalView()
+	}
+	var s string
+
+	if header := m.headerView(); header != "" {
+		s += header + "\n\n"
+	}
+	if m.committed {
+		// The files are listed in the commit summary instead.
+		return s
+	}
+
+	// Show staged files
+	s += titleStyle.Render(fileCountTitle(len(m.stagedFiles))) + "\n"
+	if m.state != stateSelectFiles {
+		s += m.filesView()
+	}
+	if len(m.stagedFiles) == 0 && m.opts.AllowEmpty {
+		s += itemStyle.Render(mutedStyle.Render("None; this is an intentionally empty commit (--allow-empty)")) + "\n"
+	}
+	s += m.notStagedView()
+	s += "\n"
+	return s
+}
+
+// statView renders the changed line counts of a staged file.
+func (m Model) statView(file string) string {
+	stat, ok := m.fileStats[file]
+	switch {
+	case !ok:
+		return ""
+	case stat.Binary:
+		return " " + mutedStyle.Render("binary")
+	}
+	return " " + addedStyle.Render(fmt.Sprintf("+%d", stat.Added)) + " " + removedStyle.Render(fmt.Sprintf("-%d", stat.Deleted)) + m.statBar(stat)
+}
+
+// maxStatBar is the width of the bar drawn for the most changed file.
+const maxStatBar = 12
+
+// statBar draws the added and deleted lines of a file as a bar of green +
+// and red -, as git diff --stat does. Bars are scaled so the most changed
+// staged file fills maxStatBar columns.
+func (m Model) statBar(stat git.FileStat) string {
+	total := stat.Added + stat.Deleted
+	if total == 0 {
+		return ""
+	}
+	var largest int
+	for _, s := range m.fileStats {
+		largest = max(largest, s.Added+s.Deleted)
+	}
+	width := total
+	if largest > maxStatBar {
+		// Round up so every changed file gets at least one column.
+		width = (total*maxStatBar + largest - 1) / largest
+	}
+	added := stat.Added * width / total
+	if stat.Added > 0 && added == 0 {
+		added = 1
+	}
+	if stat.Deleted > 0 && added == width {
+		added = max(width-1, 0)
+	}
+	return " " + addedStyle.Render(strings.Repeat("+", added)) + removedStyle.Render(strings.Repeat("-", width-added))
+}
+
+// displayHeader renders the first line of a header for the TUI, showing
+// gitmoji shortcodes as the emoji they stand for.
+func displayHeader(msg git.Message) string {
+	msg.Gitmoji = config.GitmojiToEmoji(msg.Gitmoji)
+	header, _, _ := strings.Cut(msg.Header(), "\n")
+	return header
+}
+
+func (m Model) View() string {
+	if m.NothingStaged() {
+		return "No files staged for commit. Use 'git add' to stage files.\n"
+	}
+
+	s := m.stagedView()
+
+	if m.committing {
+		s += m.spinner.View() + " Committing (running hooks)…"
+		return appStyle.Render(s)
+	}
+
+	if m.showHelp {
+		s += titleStyle.Render("Keybindings") + "\n\n"
+		bindings := m.helpBindings()
+		if m.hasMoreFiles() {
+			bindings = append(bindings, []key.Binding{m.keys.ToggleFiles, m.keys.FilesUp, m.keys.FilesDown, m.keys.ToggleMinimal})
+		} else {
+			bindings = append(bindings, []key.Binding{m.keys.ToggleMinimal})
+		}
+		s += m.help.FullHelpView(bindings) + "\n\n"
+		s += pageStyle.Render("Press any key to close")
+		return appStyle.Render(s)
+	}
+
+	switch m.state {
+	case stateSelectTemplate:
+		s += m.templates.View()
+
+	case stateSelectType:
+		if m.showDiff {
+			s += titleStyle.Render("Staged Diff") + "\n"
+			s += m.diffView.View() + "\n"
+			s += pageStyle.Render(fmt.Sprintf("%3.f%% (↑/↓, PgUp/PgDn to scroll, %s or %s to close)", m.diffView.ScrollPercent()*100, keyName(m.keys.Diff), keyName(m.keys.Back)))
+			break
+		}
+
+		// Select commit type
+		s += m.commitTypes.View() + "\n"
+		k := m.keys
+		files := ""
+		if len(m.stagedFiles) > 1 {
+			files = fmt.Sprintf(", %s to choose files", keyName(k.SelectFiles))
+		}
+		s += m.hint(fmt.Sprintf("Press %s to preview the staged diff%s, %s for a quick %q commit, %s for a fixup, %s for help",
+			keyName(k.Diff), files, keyName(k.QuickCommit), displayHeader(m.wipMessage()), keyName(k.Fixup), keyName(k.Help)))
+		if m.isBreaking {
+			// On the hint line, so the list keeps its height.
+			s += " " + breakingBadge.Render("BREAKING")
+		}
+
+	case stateEnterScope:
+		// Enter optional scope
+		s += titleStyle.Render("Commit Scope")
+		if m.lastScope != "" {
+			s += mutedStyle.Render(fmt.Sprintf(" (last: %s)", m.lastScope))
+		}
+		s += "\n"
+		s += fmt.Sprintf("Type: %s\n\n", m.selectedEmoji+m.selectedType)
+		if len(m.recentScopes) > 0 {
+			s += m.recentScopesView() + "\n"
+		}
+		s += m.scopeInput.View() + "\n"
+		if len(m.scopes) > 0 {
+			var names []string
+			for _, c := range m.scopes[:min(len(m.scopes), maxScopeHints)] {
+				names = append(names, fmt.Sprintf("%s (%d)", c.scope, c.files))
+			}
+			s += mutedStyle.Render("From staged paths: "+strings.Join(names, ", ")) + "\n"
+		}
+		s += "\n"
+		empty := "leave empty for no scope"
+		if m.branchScope != "" {
+			empty = "leave empty to use " + m.branchScope
+		}
+		hint := fmt.Sprintf("Press %s to continue (%s, Tab completes a suggestion)", keyName(m.keys.Next), empty)
+		if len(m.recentScopes) > 0 {
+			hint = fmt.Sprintf("Press %s to continue (%s, ↑/↓ picks a recent scope, Tab completes a suggestion)", keyName(m.keys.Next), empty)
+		}
+		s += m.hint(hint)
+	case stateEnterMessage:
+		// Enter commit message
+		s += titleStyle.Render("Commit Message") + "\n"
+		if m.freeForm {
+			switch {
+			case m.opts.Amend:
+				s += "Free-form message (HEAD doesn't use a known commit type)\n\n"
+			case m.inProgress == "revert":
+				s += "Free-form message (pre-filled for the revert in progress)\n\n"
+			default:
+				s += "Free-form message (pre-filled from git's merge message)\n\n"
+			}
+			s += m.textInput.View() + "\n"
+			s += m.headerCounterView() + "\n\n"
+			s += m.hint("Press " + keyName(m.keys.Back) + " to pick a commit type instead")
+			break
+		}
+		s += fmt.Sprintf("Type: %s\n", m.selectedEmoji+m.selectedType)
+		if scope := m.scope(); scope != "" {
+			s += fmt.Sprintf("Scope: %s\n", scope)
+		}
+		if m.isBreaking {
+			s += breakingBadge.Render("BREAKING") + " " + displayHeader(m.message()) + "\n"
+		}
+		if tpl := m.subjectTemplate(); tpl != "" {
+			s += fmt.Sprintf("Subject template: %s\n", tpl)
+		}
+		if m.ticket != "" {
+			if m.prependTicket {
+				s += fmt.Sprintf("Ticket: %s (prepended to the subject)\n", m.ticket)
+			} else {
+				s += fmt.Sprintf("Ticket: %s found in branch\n", m.ticket)
+			}
+		}
+		s += "\n"
+		s += m.textInput.View() + "\n"
+		s += m.headerCounterView() + "\n\n"
+		hint := "Press " + keyName(m.keys.ToggleBreaking) + " to toggle breaking change"
+		if m.ticket != "" {
+			hint += ", " + keyName(m.keys.ToggleTicket) + " to toggle the ticket prefix"
+		}
+		s += m.hint(hint)
+	case stateEnterBody:
+		// Enter optional body
+		s += titleStyle.Render("Commit Body") + "\n"
+		s += fmt.Sprintf("Subject: %s\n\n", displayHeader(m.message()))
+		s += m.bodyInput.View() + "\n\n"
+		s += m.hint(fmt.Sprintf("Press %s to continue or %s to skip the body", keyName(m.keys.FinishBody), keyName(m.keys.SkipBody)))
+	case stateEnterBreaking:
+		// Describe the breaking change
+		s += titleStyle.Render("Breaking Change") + "\n"
+		s += fmt.Sprintf("Subject: %s\n\n", displayHeader(m.message()))
+		s += m.breakingInput.View() + "\n\n"
+		s += m.hint("Press " + keyName(m.keys.Next) + " to continue (leave empty to only mark the subject)")
+	case stateEnterCoauthors:
+		// Add optional co-authors
+		s += titleStyle.Render("Co-authors") + "\n"
+		for _, coauthor := range m.coauthors {
+			s += itemStyle.Render("Co-authored-by: "+coauthor) + "\n"
+		}
+		s += "\n" + m.coauthorInput.View() + "\n"
+		if m.coauthorErr != "" {
+			s += breakingStyle.Render(m.coauthorErr) + "\n"
+		}
+		s += "\n"
+		s += m.hint("Press " + keyName(m.keys.Next) + " to add a co-author, or on an empty line to continue (Tab completes recent ones)")
+	case stateEnterRefs:
+		// Add optional issue references
+		s += titleStyle.Render("References") + "\n"
+		s += m.refInput.View() + "\n"
+		if m.refErr != "" {
+			s += breakingStyle.Render(m.refErr) + "\n"
+		}
+		s += "\n"
+		s += m.hint("Separate references with commas; press " + keyName(m.keys.Next) + " to continue")
+	case stateEnterTrailers:
+		s += m.trailersView()
+	case stateConfirm:
+		// Confirm
+		s += titleStyle.Render("Confirm Commit") + "\n"
+		if m.opts.DryRun {
+			s += warnStyle.Render("DRY RUN: the message will be printed, not committed") + "\n"
+		}
+		if m.opts.Amend {
+			s += pageStyle.Render("Amending the HEAD commit") + "\n"
+		}
+		if m.inProgress != "" {
+			s += warnStyle.Bold(true).Render(fmt.Sprintf("⚠ This commit is part of the %s in progress", m.inProgress)) + "\n"
+		}
+		if len(m.opts.Paths) > 0 {
+			s += pageStyle.Render(fmt.Sprintf("Committing %d of %d staged files", len(m.opts.Paths), len(m.stagedFiles))) + "\n"
+		}
+		if !m.freeForm {
+			s += fmt.Sprintf("Type: %s\n", m.selectedEmoji+m.selectedType)
+			switch {
+			case m.selectedScope != "":
+				s += fmt.Sprintf("Scope: %s\n", m.selectedScope)
+			case m.branchScope != "":
+				s += fmt.Sprintf("Scope: %s %s\n", m.branchScope, mutedStyle.Render("(from the branch name)"))
+			}
+			if m.prependTicket {
+				s += fmt.Sprintf("Ticket: %s\n", m.ticket)
+			}
+		}
+		subject := m.message().Subject
+		s += fmt.Sprintf("Message: %s\n", subject)
+		normalized := m.subjectRules.Normalize(m.textInput.Value())
+		if normalized != m.textInput.Value() {
+			s += pageStyle.Render(fmt.Sprintf("(normalized from %q)", m.textInput.Value())) + "\n"
+		}
+		if subject != normalized {
+			s += pageStyle.Render(fmt.Sprintf("(from the %s subject template %q)", m.selectedType, m.subjectTemplate())) + "\n"
+		}
+		if body := m.message().Body; body != "" {
+			s += "\n" + lipgloss.NewStyle().Width(m.bodyWidth()).Render(body) + "\n"
+			if m.wrapBody > 0 && body != m.body {
+				s += pageStyle.Render(fmt.Sprintf("(wrapped at %d columns)", m.wrapBody)) + "\n"
+			}
+		}
+		if m.isBreaking {
+			s += "\n" + breakingStyle.Render("⚠ BREAKING CHANGE")
+			if m.breakingDesc != "" {
+				s += breakingStyle.Render(": " + m.breakingDesc)
+			}
+			s += "\n"
+		}
+		if len(m.coauthors) > 0 {
+			s += "\n"
+			for _, coauthor := range m.coauthors {
+				s += fmt.Sprintf("Co-authored-by: %s\n", coauthor)
+			}
+		}
+		if len(m.refs) > 0 || len(m.trailers) > 0 {
+			s += "\n"
+			for _, footer := range append(slices.Clone(m.refs), m.trailers...) {
+				s += footer + "\n"
+			}
+		}
+		if m.opts.Signoff {
+			signer := m.identity
+			if signer == "" {
+				signer = "(user.name and user.email from git config)"
+			}
+			s += "\n" + fmt.Sprintf("Signed-off-by: %s\n", signer)
+		}
+		if m.opts.Author != "" {
+			s += "\n" + fmt.Sprintf("Author: %s\n", m.opts.Author)
+		}
+		if m.opts.Date != "" {
+			s += "\n" + fmt.Sprintf("Date: %s\n", m.opts.Date)
+		}
+		if m.opts.Sign {
+			s += "\n" + pageStyle.Render("🔏 Commit will be signed with "+m.signingView()) + "\n"
+		}
+		if m.opts.NoVerify {
+			s += "\n" + breakingStyle.Render("⚠ Hooks will be skipped (--no-verify)") + "\n"
+		}
+		switch {
+		case m.push && m.openPR && !m.opts.DryRun:
+			s += "\n" + pageStyle.Render("⬆ Will push and open a pull request after committing") + "\n"
+		case m.push && !m.opts.DryRun:
+			s += "\n" + pageStyle.Render("⬆ Will push after committing") + "\n"
+		}
+		s += "\n" + checksView(m.checks())
+		s += "\n" + titleStyle.Render("Command") + "\n"
+		s += lipgloss.NewStyle().Width(m.bodyWidth()).Render(mutedStyle.Render(git.CommandLine(m.message(), m.opts))) + "\n"
+		s += "\n"
+		switch worstCheck(m.checks()) {
+		case checkFail:
+			s += breakingStyle.Render("Fix the failed checks before committing; press "+keyName(m.keys.Back)+" to go back") + "\n"
+		case checkPending:
+			s += mutedStyle.Render("Waiting for the checks to finish…") + "\n"
+		case checkWarn:
+			if m.warningsAcked {
+				s += warnStyle.Render("Press "+keyName(m.keys.Next)+" again to commit despite the warnings") + "\n"
+			} else {
+				s += fmt.Sprintf("Press %s to acknowledge the warnings, %s to go back or %s to quit\n", keyName(m.keys.Next), keyName(m.keys.Back), keyName(m.keys.Quit))
+			}
+		default:
+			s += fmt.Sprintf("Press %s to commit, %s to go back or %s to quit\n", keyName(m.keys.Next), keyName(m.keys.Back), keyName(m.keys.Quit))
+		}
+		if !m.minimal {
+			k := m.keys
+			s += pageStyle.Render(fmt.Sprintf("Press %s to toggle --no-verify, %s to toggle pushing, %s to toggle signoff, %s to set the author, %s to copy the message",
+				keyName(k.ToggleNoVerify), keyName(k.TogglePush), keyName(k.ToggleSignoff), keyName(k.EditAuthor), keyName(k.CopyMessage))) + "\n"
+			s += pageStyle.Render(fmt.Sprintf("Press %s to finish the message in $EDITOR and commit it as written", keyName(k.EditMessage))) + "\n"
+			s += pageStyle.Render(m.jumpHint())
+		}
+		switch {
+		case m.editorAborted:
+			s += "\n" + warnStyle.Render("The edited message was empty; nothing was committed")
+		case m.editorErr != nil:
+			s += "\n" + breakingStyle.Render("Could not edit the message: "+m.editorErr.Error())
+		case m.copied:
+			s += "\n" + addedStyle.Render("✔ Copied!")
+		case m.copyErr != nil:
+			s += "\n" + breakingStyle.Render("Could not copy: "+m.copyErr.Error())
+		}
+	case stateEnterAuthor:
+		s += titleStyle.Render("Commit Author") + "\n"
+		s += m.authorInput.View() + "\n"
+		if m.authorErr != "" {
+			s += breakingStyle.Render(m.authorErr) + "\n"
+		}
+		s += "\n"
+		s += m.hint("Press " + keyName(m.keys.Next) + " to go back to the confirmation")
+	case stateConfirmBranch:
+		title := "Protected Branch"
+		warning := warnStyle.Render(fmt.Sprintf("⚠ %s is a protected branch.", m.branch)) + " Type yes to commit to it anyway:"
+		if m.detached {
+			title = "Detached HEAD"
+			warning = warnStyle.Render("⚠ The commit won't be on any branch.") + " Type yes to commit anyway:"
+		}
+		s += titleStyle.Render(title) + "\n"
+		s += fmt.Sprintf("Subject: %s\n\n", displayHeader(m.pendingMessage()))
+		s += warning + "\n"
+		s += m.branchInput.View() + "\n"
+		if m.branchErr != "" {
+			s += breakingStyle.Render(m.branchErr) + "\n"
+		}
+		s += "\n"
+		s += m.hint(fmt.Sprintf("Press %s to commit or %s to go back", keyName(m.keys.Next), keyName(m.keys.Back)))
+	case statePush:
+		s += m.pushView()
+	case stateSelectFiles:
+		s += m.selectFilesView()
+	case stateCommitted:
+		s += m.committedView()
+	case stateSelectFixup:
+		s += m.selectFixupView()
+	case stateCommitFailed:
+		s += m.commitFailedView()
+	case stateConfirmDuplicate:
+		s += m.confirmDuplicateView()
+	}
+	if m.jumpBack && !m.minimal {
+		s += "\n" + pageStyle.Render(fmt.Sprintf("Editing from the confirmation screen; %s or %s goes back to it", keyName(m.keys.Next), keyName(m.keys.Back)))
+	}
+
+	return m.fillScreen(appStyle.Render(s))
+}
+
+// signingView names the signing method and where its key comes from, for
+// the confirmation screen.
+func (m Model) signingView() string {
+	switch {
+	case m.signing.KeyFromConfig:
+		return m.signing.Name() + ", using user.signingKey"
+	case m.signing.Key != "":
+		return m.signing.Name() + ", using the key set for gocommit"
+	case m.signing.Format == git.SigningSSH:
+		return "SSH, using gpg.ssh.defaultKeyCommand"
+	}
+	return m.signing.Name() + ", using the key of your committer identity"
+}
+
+// headerView shows the repository and branch being committed to, with
+// protected branches in the warning color.
+func (m Model) headerView() string {
+	if m.repoRoot == "" {
+		return ""
+	}
+	s := mutedStyle.Render(filepath.Base(m.repoRoot))
+	if m.detached {
+		s += mutedStyle.Render(" on ") + warnStyle.Bold(true).Render("detached HEAD")
+		s += "\n" + warnStyle.Render("⚠ The commit won't be on any branch; create one with git switch -c <name> to keep it")
+	} else if m.branch != "" {
+		branch := m.branch
+		if m.protected {
+			branch = warnStyle.Bold(true).Render(branch)
+		}
+		s += mutedStyle.Render(" on ") + branch
+	}
+	if m.inProgress != "" {
+		s += "\n" + warnStyle.Bold(true).Render(fmt.Sprintf("⚠ A %s is in progress; this commit will be part of it", m.inProgress))
+	}
+	return s
+}
+
+// headerCounterView renders the status line under the subject: the live
+// header length, turning yellow past the recommended length and red past
+// the conventional limit, whether the header matches headerPattern, and any
+// validation error from the last attempt to continue.
+func (m Model) headerCounterView() string {
+	header := m.message().Header()
+	n := utf8.RuneCountInString(header)
+	counter := fmt.Sprintf("%d/%d", n, m.maxHeaderLen)
+	switch {
+	case n > m.maxHeaderLen:
+		counter = breakingStyle.Render(counter)
+	case n > recommendedHeaderLength:
+		counter = warnStyle.Render(counter)
+	default:
+		counter = mutedStyle.Render(counter)
+	}
+	if m.headerPattern != nil && m.textInput.Value() != "" {
+		if m.headerPattern.MatchString(header) {
+			counter += "  " + addedStyle.Render("✔ matches the pattern")
+		} else {
+			counter += "  " + breakingStyle.Render("✘ doesn't match the pattern")
+		}
+	}
+	if m.messageErr != "" {
+		counter += "  " + breakingStyle.Render(m.messageErr)
+	}
+	if m.checkMood {
+		if warning := moodWarning(m.textInput.Value()); warning != "" {
+			counter += "  " + warnStyle.Render(warning)
+		}
+	}
+	if m.speller != nil {
+		if typos := m.speller.misspelled(m.textInput.Value()); len(typos) > 0 {
+			counter += "\n  " + warnStyle.Render("Possible typos: "+strings.Join(typos, ", "))
+		}
+	}
+	return "  " + counter
+}
+
+// errorView renders the last failed commit attempt along with git's output.
+func (m Model) errorView() string {
+	s := errorTitleStyle.Render("Commit failed") + "\n"
+	s += breakingStyle.Render(m.err.Error()) + "\n"
+	if m.errOutput != "" {
+		s += lipgloss.NewStyle().Width(m.bodyWidth()).Render(m.errOutput) + "\n"
+	}
+	return s
+}
+
+// resize fits the list and inputs to the terminal, accounting for the
+// appStyle padding and the staged files shown above them. Sizes are clamped
+// so tiny terminals degrade instead of overflowing.
+func (m *Model) resize(width, height int) {
+	m.width = width
+	m.height = height
+	if !m.minimalSet {
+		m.minimal = m.minimalCfg || height < minimalHeight
+	}
+	m.commitTypes.SetShowHelp(!m.minimal)
+	m.templates.SetShowHelp(!m.minimal)
+
+	contentWidth := max(width-appStyle.GetHorizontalFrameSize(), 20)
+	// The repository header and staged files panel, and the hint line
+	// under the list.
+	reserved := appStyle.GetVerticalFrameSize() + strings.Count(m.stagedView(), "\n") + 1
+	listHeight := max(height-reserved, 6)
+	m.commitTypes.SetSize(contentWidth, listHeight)
+	m.templates.SetSize(contentWidth, listHeight)
+	m.diffView.Width = contentWidth
+	m.filesPanel.Width = contentWidth
+	m.diffView.Height = listHeight - 2
+
+	// Leave room for the "> " prompt and the cursor.
+	inputWidth := max(contentWidth-3, 10)
+	m.scopeInput.Width = inputWidth
+	m.textInput.Width = inputWidth
+	m.breakingInput.Width = inputWidth
+	m.coauthorInput.Width = inputWidth
+	m.refInput.Width = inputWidth
+	m.trailerInput.Width = inputWidth
+	m.bodyInput.SetWidth(contentWidth)
+}
+
+// bodyWidth returns the width to wrap the body to, leaving room for the
+// horizontal padding of appStyle.
+func (m Model) bodyWidth() int {
+	if m.width == 0 {
+		return 60
+	}
+	return max(m.width-appStyle.GetHorizontalFrameSize(), 20)
+}
+
+// NothingStaged reports whether there is nothing to commit.
+func (m Model) NothingStaged() bool {
+	return len(m.stagedFiles) == 0 && !m.opts.Amend && !m.opts.AllowEmpty
+}
+
+// Err returns the error from the last commit attempt, if any.
+func (m Model) Err() error {
+	return m.err
+}
+
+// DryRunOutput returns the message to print after a dry run.
+func (m Model) DryRunOutput() string {
+	return m.dryRunOutput
+}
+
+// Committed reports whether the commit was created.
+func (m Model) Committed() bool {
+	return m.committed
+}
@@ -0,0 +1,302 @@
+package ui
+
+import (
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"StevenD2002/GoCommit/config"
+	"StevenD2002/GoCommit/git"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// scriptedRunner is a GitRunner answering each command from a table keyed
+// by its space-joined arguments; other commands print nothing.
+type scriptedRunner map[string]string
+
+func (r scriptedRunner) Output(args ...string) ([]byte, error) {
+	return []byte(r[strings.Join(args, " ")]), nil
+}
+
+func (r scriptedRunner) CombinedOutput(args ...string) ([]byte, error) {
+	return r.Output(args...)
+}
+
+func (r scriptedRunner) Stream(w io.Writer, args ...string) error {
+	out, _ := r.Output(args...)
+	_, err := w.Write(out)
+	return err
+}
+
+// failingRunner is a GitRunner whose commands all fail.
+type failingRunner struct{}
+
+func (failingRunner) Output(args ...string) ([]byte, error) {
+	return nil, errors.New("git failed")
+}
+
+func (failingRunner) CombinedOutput(args ...string) ([]byte, error) {
+	return nil, errors.New("git failed")
+}
+
+func (failingRunner) Stream(w io.Writer, args ...string) error {
+	return errors.New("git failed")
+}
+
+// fakeCommitter records the commits it is asked to make.
+type fakeCommitter struct {
+	messages []git.Message
+	opts     []git.CommitOptions
+}
+
+func (f *fakeCommitter) Commit(msg git.Message, opts git.CommitOptions) (string, error) {
+	f.messages = append(f.messages, msg)
+	f.opts = append(f.opts, opts)
+	return "", nil
+}
+
+// newTestModel builds a model over a repository with main.go staged and no
+// config files, committing through c. Emoji are turned off to keep the
+// expected headers readable; configure changes the rest of the config.
+func newTestModel(t *testing.T, opts git.CommitOptions, c git.Committer, configure ...func(*config.Config)) Model {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	cfg, _, err := config.Load("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.NoEmoji = true
+	for _, f := range configure {
+		f(&cfg)
+	}
+	repo := git.NewRepo(scriptedRunner{
+		"rev-parse --is-inside-work-tree": "true",
+		"diff --name-only --cached":       "main.go",
+		"rev-parse --abbrev-ref HEAD":     "feature",
+	})
+	m, err := New(cfg, opts, repo, WithCommitter(c))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+// runCmd runs cmd and the commands of any batch it returns, and returns the
+// commitDoneMsg among their messages.
+func runCmd(t *testing.T, cmd tea.Cmd) commitDoneMsg {
+	t.Helper()
+	if cmd == nil {
+		t.Fatal("no command to run")
+	}
+	switch msg := cmd().(type) {
+	case commitDoneMsg:
+		return msg
+	case tea.BatchMsg:
+		for _, c := range msg {
+			if c == nil {
+				continue
+			}
+			if done, ok := c().(commitDoneMsg); ok {
+				return done
+			}
+		}
+	}
+	t.Fatal("the command didn't commit")
+	return commitDoneMsg{}
+}
+
+func TestCommitUsesCommitter(t *testing.T) {
+	committer := &fakeCommitter{}
+	m := newTestModel(t, git.CommitOptions{NoVerify: true}, committer)
+	m.prefill("feat(api)!: add endpoint\n\nCloses #3")
+	m.state = stateConfirm
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	runCmd(t, cmd)
+
+	if len(committer.messages) != 1 {
+		t.Fatalf("committed %d times, want 1", len(committer.messages))
+	}
+	msg := committer.messages[0]
+	if got, want := msg.Header(), "feat(api)!: add endpoint"; got != want {
+		t.Errorf("header = %q, want %q", got, want)
+	}
+	if want := []string{"Closes #3"}; !slices.Equal(msg.Refs, want) {
+		t.Errorf("refs = %q, want %q", msg.Refs, want)
+	}
+	if !committer.opts[0].NoVerify {
+		t.Error("the commit options were not passed on")
+	}
+}
+
+func TestSeedUnknownTypeIsFreeForm(t *testing.T) {
+	m := newTestModel(t, git.CommitOptions{}, &fakeCommitter{})
+	m.prefill("wip: try things\n\nCloses #3")
+
+	if !m.freeForm {
+		t.Fatal("a message with an unknown type wasn't kept free-form")
+	}
+	msg := m.message()
+	if msg.Type != "" || msg.Header() != "wip: try things" {
+		t.Errorf("message = %+v, want the header kept verbatim", msg)
+	}
+	if want := []string{"Closes #3"}; !slices.Equal(msg.Refs, want) {
+		t.Errorf("refs = %q, want %q", msg.Refs, want)
+	}
+}
+
+func TestHintsFollowRemappedKeys(t *testing.T) {
+	m := newTestModel(t, git.CommitOptions{}, &fakeCommitter{}, func(cfg *config.Config) {
+		cfg.Keys = map[string][]string{"diff": {"x"}, "next": {"ctrl+j"}, "back": {"ctrl+b"}, "quit": {"ctrl+q"}}
+	})
+	if view := m.View(); !strings.Contains(view, "Press x to preview the staged diff") {
+		t.Errorf("the type list hint doesn't name the remapped diff key:\n%s", view)
+	}
+
+	m.prefill("feat: add login")
+	m.state = stateConfirm
+	view := m.View()
+	if want := "Press Ctrl+J to commit, Ctrl+B to go back or Ctrl+Q to quit"; !strings.Contains(view, want) {
+		t.Errorf("the confirmation doesn't show %q:\n%s", want, view)
+	}
+	if strings.Contains(view, "Esc") || strings.Contains(view, "Enter") {
+		t.Errorf("the confirmation still names the default keys:\n%s", view)
+	}
+}
+
+func TestRemappedLettersStillType(t *testing.T) {
+	m := newTestModel(t, git.CommitOptions{}, &fakeCommitter{}, func(cfg *config.Config) {
+		cfg.Keys = map[string][]string{"toggleMinimal": {"m"}, "toggleFiles": {"x"}}
+	})
+	model, _ := m.enterState(stateEnterMessage)
+	for _, r := range "mix" {
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	m = model.(Model)
+	if got := m.textInput.Value(); got != "mix" {
+		t.Errorf("subject = %q, want %q", got, "mix")
+	}
+	if m.minimal {
+		t.Error("typing m in the subject toggled minimal mode")
+	}
+}
+
+func TestUpstreamHintFollowsRemappedKey(t *testing.T) {
+	m := newTestModel(t, git.CommitOptions{}, &fakeCommitter{}, func(cfg *config.Config) {
+		cfg.Keys = map[string][]string{"setUpstream": {"U"}}
+	})
+	// Without an upstream, git rev-parse @{u} fails.
+	m.repo = git.NewRepo(failingRunner{})
+	model, _ := m.Update(pushDoneMsg{err: errors.New("no upstream")})
+	output := strings.Join(model.(Model).pushOutput, "\n")
+	if want := "press U to push with -u origin feature"; !strings.Contains(output, want) {
+		t.Errorf("push output doesn't contain %q:\n%s", want, output)
+	}
+}
+
+func TestListLettersDontPage(t *testing.T) {
+	m := newTestModel(t, git.CommitOptions{}, &fakeCommitter{}, func(cfg *config.Config) {
+		cfg.Keys = map[string][]string{"diff": {"x"}, "fixup": {"ctrl+g"}}
+	})
+	m.commitTypes.SetHeight(8)
+	if m.commitTypes.Paginator.TotalPages < 2 {
+		t.Fatal("the type list needs more than one page for the test")
+	}
+	for _, r := range "dfulhbgG" {
+		model, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		if page := model.(Model).commitTypes.Paginator.Page; page != 0 {
+			t.Errorf("%c moved the type list to page %d", r, page)
+		}
+	}
+	model, _ := m.Update(tea.KeyMsg{Type: tea.KeyPgDown})
+	if page := model.(Model).commitTypes.Paginator.Page; page != 1 {
+		t.Errorf("pgdown moved the type list to page %d, want 1", page)
+	}
+}
+
+func TestClickSelectsTheItemOnThatRow(t *testing.T) {
+	for _, height := range []int{16, 30} {
+		m := newTestModel(t, git.CommitOptions{}, &fakeCommitter{})
+		model, _ := m.Update(tea.WindowSizeMsg{Width: 80, Height: height})
+		m = model.(Model)
+
+		// The renderer draws the last height lines of the view from the top
+		// row of the terminal.
+		lines := strings.Split(m.View(), "\n")
+		if len(lines) < height {
+			t.Fatalf("height %d: the view has %d lines, want it to fill the terminal", height, len(lines))
+		}
+		lines = lines[len(lines)-height:]
+		target := m.commitTypes.Index() + 1
+		title := m.commitTypes.Items()[target].(commitType).title
+		row := slices.IndexFunc(lines, func(line string) bool { return strings.Contains(line, title) })
+		if row < 0 {
+			t.Fatalf("height %d: %s isn't on screen", height, title)
+		}
+
+		model, _ = m.Update(tea.MouseMsg{X: 5, Y: row, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress})
+		if got := model.(Model).commitTypes.Index(); got != target {
+			t.Errorf("height %d: clicking row %d selected item %d, want %d (%s)", height, row, got, target, title)
+		}
+	}
+}
+
+func TestBackFromFailedCommitClearsTheError(t *testing.T) {
+	m := newTestModel(t, git.CommitOptions{}, &fakeCommitter{})
+	m.prefill("feat: add login")
+	model, _ := m.Update(commitDoneMsg{err: errors.New("pre-commit hook failed")})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = model.(Model)
+	if m.state != stateEnterMessage {
+		t.Fatalf("state = %v, want the message screen", m.state)
+	}
+	if m.Err() != nil || strings.Contains(m.View(), "pre-commit hook failed") {
+		t.Error("the commit error outlived the failed commit screen")
+	}
+}
+
+func TestHistoryKeepsTheCommittedSubject(t *testing.T) {
+	for name, commit := range map[string]func(Model) Model{
+		"edited before committing": func(m Model) Model {
+			m.edited = "fix: stop crash"
+			model, _ := m.Update(commitDoneMsg{})
+			return model.(Model)
+		},
+		"amended after committing": func(m Model) Model {
+			m.editAfter = true
+			m.repo = git.NewRepo(scriptedRunner{"log -1 --pretty=%B": "fix: stop crash\n"})
+			model, _ := m.Update(commitDoneMsg{})
+			model, _ = model.Update(amendDoneMsg{})
+			return model.(Model)
+		},
+	} {
+		m := newTestModel(t, git.CommitOptions{}, &fakeCommitter{})
+		m.repoRoot = "/repo"
+		m.prefill("feat: add login")
+		m = commit(m)
+		if got := m.history.subjects(m.repoRoot, "fix"); !slices.Equal(got, []string{"stop crash"}) {
+			t.Errorf("%s: recent subjects = %q, want the committed one", name, got)
+		}
+	}
+}
+
+func TestIdleTimeoutWaitsForRunningWork(t *testing.T) {
+	for name, set := range map[string]func(*Model){
+		"commit":       func(m *Model) { m.committing = true },
+		"push":         func(m *Model) { m.pushing = true },
+		"pull request": func(m *Model) { m.openingPR = true },
+	} {
+		m := newTestModel(t, git.CommitOptions{}, &fakeCommitter{})
+		m.idleTimeout = time.Second
+		m.lastInput = time.Now().Add(-time.Minute)
+		set(&m)
+		model, _ := m.checkIdle()
+		if model.(Model).timedOut {
+			t.Errorf("the idle timeout quit during a running %s", name)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// updateMouse lets the template and type lists be used with the mouse.
+// Clicking an item highlights it and clicking the highlighted item picks it;
+// the wheel moves the highlight. The diff preview scrolls with the wheel.
+func (m Model) updateMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if m.committing || m.showHelp {
+		return m, nil
+	}
+
+	var l *list.Model
+	switch m.state {
+	case stateSelectTemplate:
+		l = &m.templates
+	case stateSelectType:
+		if m.showDiff {
+			var cmd tea.Cmd
+			m.diffView, cmd = m.diffView.Update(msg)
+			return m, cmd
+		}
+		l = &m.commitTypes
+	default:
+		return m, nil
+	}
+	if l.FilterState() == list.Filtering {
+		return m, nil
+	}
+
+	switch {
+	case msg.Button == tea.MouseButtonWheelUp:
+		l.CursorUp()
+	case msg.Button == tea.MouseButtonWheelDown:
+		l.CursorDown()
+	case msg.Button == tea.MouseButtonLeft && msg.Action == tea.MouseActionPress:
+		index, ok := m.itemAt(*l, msg.Y)
+		if !ok {
+			return m, nil
+		}
+		if index == l.Index() {
+			return m.pick()
+		}
+		l.Select(index)
+	}
+	return m, nil
+}
+
+// usesMouse reports whether the current screen handles clicks.
+func (m Model) usesMouse() bool {
+	return m.state == stateSelectTemplate || m.state == stateSelectType
+}
+
+// fillScreen pads the view of the screens that handle clicks to the
+// terminal height. The TUI draws inline rather than on the alternate
+// screen, so a shorter view would start wherever the shell's cursor was;
+// one filling the terminal is drawn from its top row, which is what lets
+// itemAt map mouse rows to items.
+func (m Model) fillScreen(view string) string {
+	if !m.usesMouse() || m.height == 0 {
+		return view
+	}
+	if lines := lipgloss.Height(view); lines < m.height {
+		view += strings.Repeat("\n", m.height-lines)
+	}
+	return view
+}
+
+// itemAt returns the index of the list item drawn on row y of the screen.
+// fillScreen makes the view start on the top row; when it is taller than
+// the terminal, the renderer leaves out the lines at the top.
+func (m Model) itemAt(l list.Model, y int) (int, bool) {
+	if m.height == 0 {
+		// Without the terminal size, where the view starts is unknown.
+		return 0, false
+	}
+	top := appStyle.GetPaddingTop() + strings.Count(m.stagedView(), "\n")
+	top -= max(lipgloss.Height(m.View())-m.height, 0)
+	top += lipgloss.Height(l.Styles.TitleBar.Render(l.Styles.Title.Render(l.Title)))
+
+	row := y - top
+	if row < 0 || m.itemRows <= 0 {
+		return 0, false
+	}
+	start, end := l.Paginator.GetSliceBounds(len(l.VisibleItems()))
+	index := start + row/m.itemRows
+	if index >= end {
+		return 0, false
+	}
+	return index, true
+}
@@ -0,0 +1,86 @@
+package ui
+
+import (
+	"os/exec"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// prDoneMsg reports that gh pr create finished, with its output.
+type prDoneMsg struct {
+	output string
+	err    error
+}
+
+// createPRCmd runs gh pr create in the repository at root, titled and
+// described like the commit.
+func createPRCmd(root, title, body string) tea.Cmd {
+	return func() tea.Msg {
+		cmd := exec.Command("gh", "pr", "create", "--title", title, "--body", body)
+		cmd.Dir = root
+		output, err := cmd.CombinedOutput()
+		return prDoneMsg{output: strings.TrimSpace(string(output)), err: err}
+	}
+}
+
+// prSkipReason explains why no pull request is opened after the push, or
+// returns "" when one can be.
+func (m Model) prSkipReason() string {
+	switch {
+	case m.branch == "" || m.branch == "HEAD":
+		return "not on a branch"
+	case m.protected:
+		return m.branch + " is a protected branch"
+	}
+	if _, err := exec.LookPath("gh"); err != nil {
+		return "the GitHub CLI (gh) isn't installed"
+	}
+	return ""
+}
+
+// beginPR opens a pull request for the pushed branch with gh, taking the
+// title and description from the HEAD commit so an amended message is used.
+// When that isn't possible the TUI notes why and quits.
+func (m Model) beginPR() (tea.Model, tea.Cmd) {
+	if m.prSkipped = m.prSkipReason(); m.prSkipped != "" {
+		return m, tea.Quit
+	}
+	raw, err := m.repo.HeadMessage()
+	if err != nil {
+		m.prErr = err
+		return m, tea.Quit
+	}
+	title, body, _ := strings.Cut(strings.TrimSpace(raw), "\n")
+	m.openingPR = true
+	return m, tea.Batch(m.spinner.Tick, createPRCmd(m.repoRoot, title, strings.TrimSpace(body)))
+}
+
+// finishPR records the outcome of gh pr create and quits.
+func (m Model) finishPR(msg prDoneMsg) (tea.Model, tea.Cmd) {
+	m.openingPR = false
+	m.prOutput = msg.output
+	m.prErr = msg.err
+	return m, tea.Quit
+}
+
+// prView renders the pull request line under the push result.
+func (m Model) prView() string {
+	switch {
+	case m.openingPR:
+		return m.spinner.View() + " Opening a pull request…"
+	case m.prSkipped != "":
+		return mutedStyle.Render("No pull request opened: " + m.prSkipped)
+	case m.prErr != nil:
+		s := breakingStyle.Render("Could not open a pull request: " + m.prErr.Error())
+		if m.prOutput != "" {
+			s += "\n" + mutedStyle.Render(m.prOutput)
+		}
+		return s
+	case m.prOutput != "":
+		// gh prints the URL of the new pull request last.
+		lines := strings.Split(m.prOutput, "\n")
+		return addedStyle.Render("✔ Opened " + lines[len(lines)-1])
+	}
+	return ""
+}
@@ -0,0 +1,124 @@
+package ui
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+
+	"StevenD2002/GoCommit/git"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxPushLines caps how much push output is kept for display.
+const maxPushLines = 15
+
+// pushLineMsg carries one line of git push output.
+type pushLineMsg string
+
+// pushDoneMsg reports that git push finished.
+type pushDoneMsg struct{ err error }
+
+// startPush runs git push in the background and streams its output over
+// the returned channel, finishing with a pushDoneMsg.
+func startPush(repo *git.Repo, setUpstream bool, branch string) chan tea.Msg {
+	ch := make(chan tea.Msg)
+	go func() {
+		defer close(ch)
+		r, w := io.Pipe()
+		done := make(chan error, 1)
+		go func() {
+			done <- repo.Push(w, setUpstream, branch)
+			w.Close()
+		}()
+
+		scanner := bufio.NewScanner(r)
+		scanner.Split(scanProgressLines)
+		for scanner.Scan() {
+			if line := scanner.Text(); line != "" {
+				ch <- pushLineMsg(line)
+			}
+		}
+		ch <- pushDoneMsg{err: <-done}
+	}()
+	return ch
+}
+
+// waitForPush returns a command that delivers the next message from a push.
+func waitForPush(ch chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		return <-ch
+	}
+}
+
+// scanProgressLines splits on both newlines and the carriage returns git
+// uses to redraw progress counters.
+func scanProgressLines(data []byte, atEOF bool) (int, []byte, error) {
+	if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+		return i + 1, data[:i], nil
+	}
+	if atEOF && len(data) > 0 {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
+
+// beginPush switches to the push step and starts git push.
+func (m Model) beginPush(setUpstream bool) (tea.Model, tea.Cmd) {
+	m.state = statePush
+	m.pushing = true
+	m.pushErr = nil
+	m.pushOutput = nil
+	m.pushCh = startPush(m.repo, setUpstream, m.branch)
+	return m, tea.Batch(m.spinner.Tick, waitForPush(m.pushCh))
+}
+
+// updatePush handles keys on the push step. Failed pushes can be retried,
+// optionally setting the upstream, without leaving the TUI.
+func (m Model) updatePush(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.pushing || m.openingPR {
+		return m, nil
+	}
+	switch {
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+	case key.Matches(msg, m.keys.RetryPush) && m.pushErr != nil:
+		return m.beginPush(false)
+	case key.Matches(msg, m.keys.SetUpstream) && m.pushErr != nil && m.branch != "" && m.branch != "HEAD":
+		return m.beginPush(true)
+	}
+	return m, nil
+}
+
+// pushView renders the streamed push output and its result.
+func (m Model) pushView() string {
+	s := m.commitSummary() + "\n"
+	s += titleStyle.Render("Push") + "\n"
+	lines := m.pushOutput
+	if len(lines) > maxPushLines {
+		lines = lines[len(lines)-maxPushLines:]
+	}
+	for _, line := range lines {
+		s += mutedStyle.Render(line) + "\n"
+	}
+	s += "\n"
+
+	switch {
+	case m.pushing:
+		s += m.spinner.View() + " Pushing…"
+	case m.pushErr != nil:
+		s += breakingStyle.Render("Push failed: "+m.pushErr.Error()) + "\n\n"
+		hint := "Press " + keyName(m.keys.RetryPush) + " to retry"
+		if m.branch != "" && m.branch != "HEAD" {
+			hint += fmt.Sprintf(", %s to push with -u origin %s", keyName(m.keys.SetUpstream), m.branch)
+		}
+		s += pageStyle.Render(hint + " or " + keyName(m.keys.Quit) + " to quit")
+	default:
+		s += addedStyle.Render("✔ Pushed")
+		if pr := m.prView(); pr != "" {
+			s += "\n" + pr
+		}
+	}
+	return s
+}
@@ -0,0 +1,137 @@
+package ui
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"StevenD2002/GoCommit/git"
+)
+
+// scopeContainers are directories that hold packages rather than name one,
+// so the directory below them is used as the scope instead.
+var scopeContainers = []string{"pkg", "internal", "cmd", "src", "lib", "apps", "packages", "services"}
+
+// maxScopeHints caps how many suggested scopes are listed in the scope step.
+const maxScopeHints = 5
+
+// maxScopeCommits is how many commits are read for recent scopes, and
+// maxRecentScopes how many of them the scope step offers.
+const (
+	maxScopeCommits = 200
+	maxRecentScopes = 5
+)
+
+// scopeCandidate is a scope suggested from the staged paths.
+type scopeCandidate struct {
+	scope string
+	files int
+}
+
+// scopeCandidates derives scopes from the directories of the staged files,
+// ranked by how many files fall under each.
+func scopeCandidates(files []string) []scopeCandidate {
+	counts := make(map[string]int)
+	var order []string
+	for _, file := range files {
+		area := fileArea(file)
+		if area == "" {
+			continue
+		}
+		// The staged files are grouped by the same directories.
+		scope := path.Base(area)
+		if counts[scope] == 0 {
+			order = append(order, scope)
+		}
+		counts[scope]++
+	}
+
+	candidates := make([]scopeCandidate, 0, len(order))
+	for _, scope := range order {
+		candidates = append(candidates, scopeCandidate{scope: scope, files: counts[scope]})
+	}
+	// Stable, so ties keep the order the files were listed in.
+	slices.SortStableFunc(candidates, func(a, b scopeCandidate) int {
+		return b.files - a.files
+	})
+	return candidates
+}
+
+// recentScope is a scope used in the repository's recent history.
+type recentScope struct {
+	scope string
+	uses  int
+}
+
+// recentScopes extracts the scopes of conventional commit subjects, ranked
+// by how often each was used, up to maxRecentScopes. Ties keep the most
+// recently used first.
+func recentScopes(entries []git.LogEntry) []recentScope {
+	counts := make(map[string]int)
+	var order []string
+	for _, entry := range entries {
+		msg, ok := git.ParseConventional(entry.Subject)
+		scope := strings.TrimSpace(msg.Scope)
+		if !ok || scope == "" {
+			continue
+		}
+		if counts[scope] == 0 {
+			order = append(order, scope)
+		}
+		counts[scope]++
+	}
+
+	scopes := make([]recentScope, 0, len(order))
+	for _, scope := range order {
+		scopes = append(scopes, recentScope{scope: scope, uses: counts[scope]})
+	}
+	slices.SortStableFunc(scopes, func(a, b recentScope) int {
+		return b.uses - a.uses
+	})
+	return scopes[:min(len(scopes), maxRecentScopes)]
+}
+
+// recentScopeIndex returns the position of the scope input's value among
+// the recent scopes, or -1.
+func (m Model) recentScopeIndex() int {
+	value := strings.TrimSpace(m.scopeInput.Value())
+	return slices.IndexFunc(m.recentScopes, func(r recentScope) bool { return r.scope == value })
+}
+
+// moveScope picks the next (delta 1) or previous (delta -1) recent scope
+// into the scope input. Moving up past the first one brings back what was
+// typed before the list was used.
+func (m Model) moveScope(delta int) Model {
+	i := m.recentScopeIndex()
+	if i < 0 {
+		if delta < 0 {
+			return m
+		}
+		m.scopeDraft = m.scopeInput.Value()
+	}
+	switch i += delta; {
+	case i < 0:
+		m.scopeInput.SetValue(m.scopeDraft)
+	case i < len(m.recentScopes):
+		m.scopeInput.SetValue(m.recentScopes[i].scope)
+	}
+	m.scopeInput.CursorEnd()
+	return m
+}
+
+// recentScopesView lists the recent scopes with their use counts, marking
+// the one in the scope input.
+func (m Model) recentScopesView() string {
+	s := mutedStyle.Render("Recent scopes") + "\n"
+	current := m.recentScopeIndex()
+	for i, r := range m.recentScopes {
+		line := r.scope + mutedStyle.Render(fmt.Sprintf(" (%d)", r.uses))
+		if i == current {
+			s += "  > " + line + "\n"
+		} else {
+			s += itemStyle.Render(line) + "\n"
+		}
+	}
+	return s
+}
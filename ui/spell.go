@@ -0,0 +1,102 @@
+package ui
+
+import (
+	_ "embed"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// words.txt is a list of lowercase English and programming words, one per
+// line, with the -ed, -ing and comparative forms spelled out. It is written
+// by hand for gocommit rather than taken from a published dictionary, so it is
+// covered by gocommit's own licence. Keep it sorted.
+//
+//go:embed words.txt
+var wordList string
+
+// dictionary returns the embedded word list as a set, built on first use.
+var dictionary = sync.OnceValue(func() map[string]bool {
+	words := make(map[string]bool, strings.Count(wordList, "\n"))
+	for _, w := range strings.Fields(wordList) {
+		words[w] = true
+	}
+	return words
+})
+
+// speller flags subject words that are neither in the embedded dictionary
+// nor in the repository's own word list.
+type speller struct {
+	custom map[string]bool
+}
+
+// newSpeller returns a speller that also accepts the given words, such as
+// project jargon. Matching ignores case.
+func newSpeller(words []string) *speller {
+	custom := make(map[string]bool, len(words))
+	for _, w := range words {
+		custom[strings.ToLower(w)] = true
+	}
+	return &speller{custom: custom}
+}
+
+// misspelled returns the words of the subject that look like typos. Words
+// that look like code, such as identifiers, paths or acronyms, are skipped.
+func (s *speller) misspelled(subject string) []string {
+	var typos []string
+	for _, field := range strings.Fields(subject) {
+		word := strings.TrimFunc(field, func(r rune) bool {
+			return !unicode.IsLetter(r)
+		})
+		if len(word) < 2 || s.custom[strings.ToLower(word)] || looksLikeCode(word) {
+			continue
+		}
+		for _, part := range strings.Split(word, "-") {
+			if part != "" && !s.known(strings.ToLower(part)) {
+				typos = append(typos, word)
+				break
+			}
+		}
+	}
+	return typos
+}
+
+// looksLikeCode reports whether a word is an identifier, path, acronym or
+// similar rather than prose.
+func looksLikeCode(word string) bool {
+	for i, r := range word {
+		switch {
+		case unicode.IsDigit(r), strings.ContainsRune("_./:=()[]{}<>#@`", r):
+			return true
+		case i > 0 && unicode.IsUpper(r):
+			// camelCase, PascalCase and ACRONYMS.
+			return true
+		}
+	}
+	return false
+}
+
+// known reports whether a lowercase word, or its singular, is in the
+// dictionary or the custom words. Other inflections are in the word list
+// themselves, so misspelled ones like "occured" aren't accepted by stemming.
+func (s *speller) known(word string) bool {
+	word = strings.TrimSuffix(word, "'s")
+	candidates := []string{word}
+	if stem, ok := strings.CutSuffix(word, "s"); ok {
+		candidates = append(candidates, stem)
+	}
+	if stem, ok := strings.CutSuffix(word, "es"); ok {
+		candidates = append(candidates, stem)
+	}
+	if stem, ok := strings.CutSuffix(word, "ies"); ok {
+		candidates = append(candidates, stem+"y")
+	}
+
+	dict := dictionary()
+	for _, c := range candidates {
+		if dict[c] || s.custom[c] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,41 @@
+package ui
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestMisspelled(t *testing.T) {
+	s := newSpeller([]string{"gRPC"})
+	tests := []struct {
+		subject string
+		want    []string
+	}{
+		{"add login and retry failed uploads", nil},
+		{"handle timeouts while pushing the branch", nil},
+		{"don't panic when the config file is missing", nil},
+		{"simplified the parser's error messages", nil},
+		{"add gRPC client in pkg/api for parseConfig", nil},
+		{"fix login adn logout", []string{"adn"}},
+		{"handle occured errors", []string{"occured"}},
+		{"teh quick fix", []string{"teh"}},
+	}
+	for _, tt := range tests {
+		if got := s.misspelled(tt.subject); !slices.Equal(got, tt.want) {
+			t.Errorf("misspelled(%q) = %q, want %q", tt.subject, got, tt.want)
+		}
+	}
+}
+
+func TestWordListIsSortedAndLowercase(t *testing.T) {
+	words := strings.Fields(wordList)
+	if !slices.IsSorted(words) {
+		t.Error("words.txt isn't sorted")
+	}
+	for _, w := range words {
+		if w != strings.ToLower(w) {
+			t.Errorf("words.txt has %q, which isn't lowercase", w)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package ui
+
+import (
+	"strings"
+
+	"StevenD2002/GoCommit/config"
+	"github.com/charmbracelet/bubbles/list"
+)
+
+// templateItem is an entry in the template picker. The zero value is the
+// "no template" entry.
+type templateItem struct {
+	template config.Template
+}
+
+func (t templateItem) Title() string {
+	if t.template.Name == "" {
+		return "No template"
+	}
+	return t.template.Name
+}
+
+func (t templateItem) Description() string {
+	if t.template.Name == "" {
+		return "Start from an empty message"
+	}
+	var parts []string
+	for _, p := range []string{t.template.Type, t.template.Scope, t.template.Subject} {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return strings.Join(parts, " · ")
+}
+
+func (t templateItem) FilterValue() string { return t.template.Name }
+
+// newTemplateList builds the picker shown before the type list.
+func newTemplateList(templates []config.Template, delegate list.ItemDelegate, keys keyMap) list.Model {
+	items := []list.Item{templateItem{}}
+	for _, t := range templates {
+		items = append(items, templateItem{template: t})
+	}
+	l := list.New(items, delegate, 60, 20)
+	l.SetShowStatusBar(false)
+	l.SetFilteringEnabled(false)
+	l.Styles.Title = titleStyle
+	l.Title = "Select template"
+	keys.applyListKeys(&l)
+	return l
+}
+
+// applyTemplate pre-populates the flow from a template.
+func (m *Model) applyTemplate(t config.Template) {
+	for i, item := range m.commitTypes.Items() {
+		if item.(commitType).title == t.Type {
+			m.commitTypes.Select(i)
+			break
+		}
+	}
+	if t.Scope == "" {
+		t.Scope = m.lastScope
+	}
+	m.scopeInput.SetValue(t.Scope)
+	m.textInput.SetValue(t.Subject)
+	m.bodyInput.SetValue(t.Body)
+	m.trailers = t.Footers
+}
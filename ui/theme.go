@@ -0,0 +1,18 @@
+package ui
+
+import (
+	"StevenD2002/GoCommit/config"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// applyTheme updates the package styles to use the theme's colors.
+func applyTheme(t config.Theme) {
+	titleStyle = titleStyle.
+		Foreground(lipgloss.Color(t.TitleForeground)).
+		Background(lipgloss.Color(t.TitleBackground))
+	if t.Item != "" {
+		itemStyle = itemStyle.Foreground(lipgloss.Color(t.Item))
+	}
+	pageStyle = pageStyle.Foreground(lipgloss.Color(t.Hint))
+	mutedStyle = mutedStyle.Foreground(lipgloss.Color(t.Hint))
+}
@@ -0,0 +1,92 @@
+package ui
+
+import (
+	"slices"
+	"strings"
+
+	"StevenD2002/GoCommit/git"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// trailerKeys are the keys suggested in the trailers step. Co-authors,
+// references and Signed-off-by have their own steps and toggle.
+var trailerKeys = []string{
+	"Acked-by", "Cc", "Fixes", "Helped-by", "Reported-by", "Reviewed-by",
+	"Suggested-by", "Tested-by",
+}
+
+// maxTrailerMatches is how many matching keys are listed under the input.
+const maxTrailerMatches = 5
+
+// newTrailerInput returns the input of the trailers step, completing the
+// common keys.
+func newTrailerInput() textinput.Model {
+	ti := textinput.New()
+	ti.Placeholder = "Key: value, e.g. Reviewed-by: Name <email> (leave empty to continue)"
+	ti.Width = 60
+	ti.ShowSuggestions = true
+	suggestions := make([]string, len(trailerKeys))
+	for i, k := range trailerKeys {
+		suggestions[i] = k + ": "
+	}
+	ti.SetSuggestions(suggestions)
+	return ti
+}
+
+// addTrailer adds the typed trailer, or moves on when the input is empty.
+// Keys matching a common one are written with its capitalization.
+func (m Model) addTrailer() (tea.Model, tea.Cmd) {
+	value := strings.TrimSpace(m.trailerInput.Value())
+	if value == "" {
+		m.trailerErr = ""
+		return m.advance()
+	}
+	key, value, err := git.ParseTrailer(value)
+	if err != nil {
+		m.trailerErr = err.Error()
+		return m, nil
+	}
+	if i := slices.IndexFunc(trailerKeys, func(k string) bool { return strings.EqualFold(k, key) }); i >= 0 {
+		key = trailerKeys[i]
+	}
+	if trailer := key + ": " + value; !slices.Contains(m.trailers, trailer) {
+		m.trailers = append(m.trailers, trailer)
+	}
+	m.trailerErr = ""
+	m.trailerInput.Reset()
+	return m, nil
+}
+
+// removeTrailer drops the last trailer, for backspace on an empty input.
+func (m Model) removeTrailer() Model {
+	m.trailers = m.trailers[:len(m.trailers)-1]
+	m.trailerErr = ""
+	return m
+}
+
+// trailersView renders the trailers step: the trailers added so far, the
+// input and the keys matching what was typed.
+func (m Model) trailersView() string {
+	s := titleStyle.Render("Trailers") + "\n"
+	for _, trailer := range m.trailers {
+		s += itemStyle.Render(trailer) + "\n"
+	}
+	s += "\n" + m.trailerInput.View() + "\n"
+	if matches := m.trailerInput.MatchedSuggestions(); len(matches) > 0 && !strings.Contains(m.trailerInput.Value(), ":") {
+		current := m.trailerInput.CurrentSuggestionIndex()
+		for i, match := range matches[:min(len(matches), maxTrailerMatches)] {
+			if i == current {
+				s += "  > " + strings.TrimSuffix(match, ": ") + "\n"
+			} else {
+				s += itemStyle.Render(mutedStyle.Render(strings.TrimSuffix(match, ": "))) + "\n"
+			}
+		}
+	}
+	if m.trailerErr != "" {
+		s += breakingStyle.Render(m.trailerErr) + "\n"
+	}
+	s += "\n"
+	s += m.hint("Press " + keyName(m.keys.Next) + " to add a trailer, or on an empty line to continue (Tab completes the key, Backspace removes the last one)")
+	return s
+}
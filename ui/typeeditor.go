@@ -0,0 +1,292 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"StevenD2002/GoCommit/config"
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// typeEditorKeyMap holds the bindings of the type editor.
+type typeEditorKeyMap struct {
+	Up        key.Binding
+	Down      key.Binding
+	MoveUp    key.Binding
+	MoveDown  key.Binding
+	Add       key.Binding
+	Edit      key.Binding
+	Delete    key.Binding
+	Save      key.Binding
+	Quit      key.Binding
+	NextField key.Binding
+	PrevField key.Binding
+	Confirm   key.Binding
+	Cancel    key.Binding
+	ForceQuit key.Binding
+}
+
+func defaultTypeEditorKeyMap() typeEditorKeyMap {
+	return typeEditorKeyMap{
+		Up:        key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("↑/k", "up")),
+		Down:      key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("↓/j", "down")),
+		MoveUp:    key.NewBinding(key.WithKeys("shift+up", "K"), key.WithHelp("K", "move up")),
+		MoveDown:  key.NewBinding(key.WithKeys("shift+down", "J"), key.WithHelp("J", "move down")),
+		Add:       key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "add")),
+		Edit:      key.NewBinding(key.WithKeys("e", "enter"), key.WithHelp("e", "edit")),
+		Delete:    key.NewBinding(key.WithKeys("d", "x"), key.WithHelp("d", "delete")),
+		Save:      key.NewBinding(key.WithKeys("s", "ctrl+s"), key.WithHelp("s", "save")),
+		Quit:      key.NewBinding(key.WithKeys("q", "esc"), key.WithHelp("q", "quit")),
+		NextField: key.NewBinding(key.WithKeys("tab", "down"), key.WithHelp("tab", "next field")),
+		PrevField: key.NewBinding(key.WithKeys("shift+tab", "up"), key.WithHelp("shift+tab", "previous field")),
+		Confirm:   key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "done")),
+		Cancel:    key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "cancel")),
+		ForceQuit: key.NewBinding(key.WithKeys("ctrl+c"), key.WithHelp("ctrl+c", "quit")),
+	}
+}
+
+// Fields of the type form, in tab order.
+const (
+	fieldTitle = iota
+	fieldDesc
+	fieldEmoji
+)
+
+// TypeEditor is the screen for adding, removing and reordering the commit
+// types. Changes are written back to the config file when saved.
+type TypeEditor struct {
+	types       []config.Type
+	path        string
+	cursor      int
+	editing     bool
+	editIndex   int
+	inputs      []textinput.Model
+	focus       int
+	err         string
+	dirty       bool
+	saved       bool
+	confirmQuit bool
+	keys        typeEditorKeyMap
+	help        help.Model
+}
+
+// NewTypeEditor returns an editor for the configured types that saves to
+// the config file at path.
+func NewTypeEditor(cfg config.Config, path string) (TypeEditor, error) {
+	theme, err := cfg.Theme.Resolve()
+	if err != nil {
+		return TypeEditor{}, err
+	}
+	applyTheme(theme)
+
+	placeholders := []string{"Title, e.g. feat", "Description", "Emoji (optional)"}
+	inputs := make([]textinput.Model, len(placeholders))
+	for i, p := range placeholders {
+		inputs[i] = textinput.New()
+		inputs[i].Placeholder = p
+		inputs[i].Width = 60
+	}
+	inputs[fieldTitle].CharLimit = 30
+
+	return TypeEditor{
+		types:  slices.Clone(cfg.Types),
+		path:   path,
+		inputs: inputs,
+		keys:   defaultTypeEditorKeyMap(),
+		help:   help.New(),
+	}, nil
+}
+
+func (e TypeEditor) Init() tea.Cmd {
+	return nil
+}
+
+// Saved reports whether the types were written to the config file.
+func (e TypeEditor) Saved() bool {
+	return e.saved
+}
+
+func (e TypeEditor) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return e, nil
+	}
+	if key.Matches(keyMsg, e.keys.ForceQuit) {
+		return e, tea.Quit
+	}
+	if e.editing {
+		return e.updateForm(keyMsg)
+	}
+
+	// Any other key cancels a pending quit.
+	quitting := e.confirmQuit
+	e.confirmQuit = false
+
+	switch {
+	case key.Matches(keyMsg, e.keys.Quit):
+		if e.dirty && !quitting {
+			e.confirmQuit = true
+			return e, nil
+		}
+		return e, tea.Quit
+	case key.Matches(keyMsg, e.keys.Up):
+		e.cursor = max(e.cursor-1, 0)
+	case key.Matches(keyMsg, e.keys.Down):
+		e.cursor = min(e.cursor+1, len(e.types)-1)
+	case key.Matches(keyMsg, e.keys.MoveUp) && e.cursor > 0:
+		e.types[e.cursor-1], e.types[e.cursor] = e.types[e.cursor], e.types[e.cursor-1]
+		e.cursor--
+		e.dirty = true
+	case key.Matches(keyMsg, e.keys.MoveDown) && e.cursor < len(e.types)-1:
+		e.types[e.cursor+1], e.types[e.cursor] = e.types[e.cursor], e.types[e.cursor+1]
+		e.cursor++
+		e.dirty = true
+	case key.Matches(keyMsg, e.keys.Add):
+		return e.openForm(-1)
+	case key.Matches(keyMsg, e.keys.Edit) && len(e.types) > 0:
+		return e.openForm(e.cursor)
+	case key.Matches(keyMsg, e.keys.Delete) && len(e.types) > 0:
+		if len(e.types) == 1 {
+			e.err = "At least one commit type is required"
+			return e, nil
+		}
+		e.types = slices.Delete(e.types, e.cursor, e.cursor+1)
+		e.cursor = min(e.cursor, len(e.types)-1)
+		e.dirty = true
+	case key.Matches(keyMsg, e.keys.Save):
+		if err := config.SaveTypes(e.path, e.types); err != nil {
+			e.err = err.Error()
+			return e, nil
+		}
+		e.saved = true
+		e.dirty = false
+	}
+	e.err = ""
+	return e, nil
+}
+
+// openForm starts editing the type at index, or a new one when index is -1.
+func (e TypeEditor) openForm(index int) (tea.Model, tea.Cmd) {
+	var t config.Type
+	if index >= 0 {
+		t = e.types[index]
+	}
+	e.inputs[fieldTitle].SetValue(t.Title)
+	e.inputs[fieldDesc].SetValue(t.Desc)
+	e.inputs[fieldEmoji].SetValue(t.Emoji)
+	e.editing = true
+	e.editIndex = index
+	e.err = ""
+	return e.focusField(fieldTitle)
+}
+
+// focusField moves the cursor to one of the form inputs.
+func (e TypeEditor) focusField(field int) (tea.Model, tea.Cmd) {
+	for i := range e.inputs {
+		e.inputs[i].Blur()
+	}
+	e.focus = field
+	return e, e.inputs[field].Focus()
+}
+
+// updateForm handles keys while adding or editing a type.
+func (e TypeEditor) updateForm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, e.keys.Cancel):
+		e.editing = false
+		e.err = ""
+		return e, nil
+	case key.Matches(msg, e.keys.NextField):
+		return e.focusField((e.focus + 1) % len(e.inputs))
+	case key.Matches(msg, e.keys.PrevField):
+		return e.focusField((e.focus + len(e.inputs) - 1) % len(e.inputs))
+	case key.Matches(msg, e.keys.Confirm):
+		t := config.Type{
+			Title: strings.TrimSpace(e.inputs[fieldTitle].Value()),
+			Desc:  strings.TrimSpace(e.inputs[fieldDesc].Value()),
+			Emoji: strings.TrimSpace(e.inputs[fieldEmoji].Value()),
+		}
+		if t.Title == "" {
+			e.err = "The title can't be empty"
+			return e.focusField(fieldTitle)
+		}
+		for i, other := range e.types {
+			if i != e.editIndex && other.Title == t.Title {
+				e.err = fmt.Sprintf("There already is a %q type", t.Title)
+				return e.focusField(fieldTitle)
+			}
+		}
+
+		if e.editIndex >= 0 {
+			t.Gitmoji = e.types[e.editIndex].Gitmoji
+			t.Subject = e.types[e.editIndex].Subject
+			t.Keywords = e.types[e.editIndex].Keywords
+			e.types[e.editIndex] = t
+		} else {
+			e.types = append(e.types, t)
+			e.cursor = len(e.types) - 1
+		}
+		e.editing = false
+		e.dirty = true
+		e.err = ""
+		return e, nil
+	}
+
+	var cmd tea.Cmd
+	e.inputs[e.focus], cmd = e.inputs[e.focus].Update(msg)
+	return e, cmd
+}
+
+func (e TypeEditor) View() string {
+	s := titleStyle.Render("Manage Commit Types") + "\n"
+	s += pageStyle.Render(e.path) + "\n\n"
+
+	if e.editing {
+		title := "New type"
+		if e.editIndex >= 0 {
+			title = "Edit " + e.types[e.editIndex].Title
+		}
+		s += title + "\n\n"
+		for _, input := range e.inputs {
+			s += input.View() + "\n"
+		}
+		if e.err != "" {
+			s += "\n" + breakingStyle.Render(e.err) + "\n"
+		}
+		s += "\n" + e.help.ShortHelpView([]key.Binding{e.keys.Confirm, e.keys.NextField, e.keys.Cancel})
+		return appStyle.Render(s)
+	}
+
+	for i, t := range e.types {
+		line := t.Emoji + t.Title
+		if t.Desc != "" {
+			line += mutedStyle.Render(" – " + t.Desc)
+		}
+		if i == e.cursor {
+			s += "> " + line + "\n"
+		} else {
+			s += "  " + line + "\n"
+		}
+	}
+	s += "\n"
+
+	switch {
+	case e.err != "":
+		s += breakingStyle.Render(e.err) + "\n"
+	case e.confirmQuit:
+		s += warnStyle.Render("Unsaved changes; press q again to discard them") + "\n"
+	case e.dirty:
+		s += warnStyle.Render("Unsaved changes") + "\n"
+	case e.saved:
+		s += addedStyle.Render("✔ Saved") + "\n"
+	}
+	s += e.help.ShortHelpView([]key.Binding{
+		e.keys.Up, e.keys.Down, e.keys.MoveUp, e.keys.MoveDown,
+		e.keys.Add, e.keys.Edit, e.keys.Delete, e.keys.Save, e.keys.Quit,
+	})
+	return appStyle.Render(s)
+}
@@ -0,0 +1,90 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// shortSHALength is how much of the commit hash the post-commit screen shows.
+const shortSHALength = 7
+
+// canUndo reports whether the commit made in this session can be undone.
+// Amending rewrote an existing commit, so resetting to its parent would lose
+// that commit too.
+func (m Model) canUndo() bool {
+	return m.committed && m.commitSHA != "" && !m.opts.Amend
+}
+
+// updateCommitted handles keys on the screen shown after committing.
+func (m Model) updateCommitted(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Undo) && m.canUndo():
+		if err := m.repo.UndoCommit(m.commitSHA); err != nil {
+			m.undoErr = err
+			return m, nil
+		}
+		m.committed = false
+		m.commitSHA = ""
+		m.undoErr = nil
+		m.edited = ""
+		m.amended = ""
+		m.amendErr = nil
+		if m.quickCommit {
+			m.quickCommit = false
+			return m.enterState(stateSelectType)
+		}
+		return m.enterState(stateEnterMessage)
+	case key.Matches(msg, m.keys.Next), key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+// committedView renders the new commit and the undo hint.
+func (m Model) committedView() string {
+	s := m.commitSummary() + "\n"
+	if m.undoErr != nil {
+		s += breakingStyle.Render("Could not undo: "+m.undoErr.Error()) + "\n\n"
+	}
+	if m.canUndo() {
+		s += pageStyle.Render(fmt.Sprintf("Press %s to undo the commit and keep editing, %s or %s to exit", keyName(m.keys.Undo), keyName(m.keys.Next), keyName(m.keys.Quit)))
+	} else {
+		s += pageStyle.Render(fmt.Sprintf("Press %s or %s to exit", keyName(m.keys.Next), keyName(m.keys.Quit)))
+	}
+	return s
+}
+
+// commitSummary renders the success panel: the short hash and header of the
+// new commit, the branch it went to and the files it contains.
+func (m Model) commitSummary() string {
+	s := titleStyle.Render("Committed") + "\n"
+	sha := m.commitSHA
+	if len(sha) > shortSHALength {
+		sha = sha[:shortSHALength]
+	}
+	header := displayHeader(m.pendingMessage())
+	if m.amended != "" {
+		header, _, _ = strings.Cut(m.amended, "\n")
+	}
+	s += addedStyle.Render("✔ "+sha) + " " + header + "\n"
+	if m.branch != "" && m.branch != "HEAD" {
+		s += pageStyle.Render("on "+m.branch) + "\n"
+	}
+	if m.amendErr != nil {
+		s += warnStyle.Render("git commit --amend failed ("+m.amendErr.Error()+"); the commit was kept as it was") + "\n"
+	}
+	s += "\n"
+
+	files := m.selectedFiles(m.excluded)
+	if len(files) > 0 {
+		s += m.fileLines(files[:min(len(files), collapsedFiles)]) + "\n"
+		if more := len(files) - collapsedFiles; more > 0 {
+			s += itemStyle.Render(mutedStyle.Render(fmt.Sprintf("… and %d more", more))) + "\n"
+		}
+		s += "\n"
+	}
+	return s
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// Build information, set with -ldflags "-X main.version=... -X main.commit=...
+// -X main.date=...". Builds without them fall back to the module and VCS
+// details recorded by the Go toolchain.
+var (
+	version = "dev"
+	commit  = ""
+	date    = ""
+)
+
+// versionString describes the running binary.
+func versionString() string {
+	v, c, d := version, commit, date
+	if info, ok := debug.ReadBuildInfo(); ok {
+		if v == "dev" && info.Main.Version != "" && info.Main.Version != "(devel)" {
+			v = info.Main.Version
+		}
+		for _, s := range info.Settings {
+			switch {
+			case s.Key == "vcs.revision" && c == "":
+				c = s.Value
+			case s.Key == "vcs.time" && d == "":
+				d = s.Value
+			}
+		}
+	}
+	if c == "" {
+		c = "unknown"
+	}
+	if d == "" {
+		d = "unknown"
+	}
+	return fmt.Sprintf("gocommit %s (commit %s, built %s)", v, c, d)
+}